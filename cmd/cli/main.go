@@ -1,88 +1,283 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strings"
+	"strconv"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/hive-corporation/watchtower/internal/adapter/manifest"
 	pb "github.com/hive-corporation/watchtower/proto"
 )
 
 func main() {
-	targetFile := flag.String("file", "go.mod", "Caminho para o go.mod")
+	targetFile := flag.String("file", "go.mod", "Caminho para o manifesto/lockfile (go.mod, package-lock.json, Cargo.lock, ...)")
+	targetDir := flag.String("dir", "", "Diretório para varredura recursiva de manifestos (sobrepõe -file)")
 	serverAddr := flag.String("server", "localhost:50051", "Endereço da API Watchtower")
+	sarifOut := flag.String("sarif", "", "Caminho para gravar os resultados em formato SARIF 2.1.0 (GitHub code scanning)")
+	apiKey := flag.String("api-key", "", "API key to authenticate with the Watchtower gRPC API (sent as x-api-key)")
+	caFile := flag.String("ca", "", "PEM CA bundle to verify the server (and enable TLS)")
+	certFile := flag.String("cert", "", "Client certificate for mTLS (requires -key and -ca)")
+	keyFile := flag.String("key", "", "Client private key for mTLS (requires -cert and -ca)")
+	oidcToken := flag.String("oidc-token", "", "OIDC bearer token to authenticate with the Watchtower gRPC API")
 	flag.Parse()
 
-	conn, err := grpc.Dial(*serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	dialOpts, err := dialOptions(*caFile, *certFile, *keyFile, *apiKey, *oidcToken)
+	if err != nil {
+		log.Fatalf("❌ invalid auth flags: %v", err)
+	}
+
+	conn, err := grpc.Dial(*serverAddr, dialOpts...)
 	if err != nil {
 		log.Fatalf("❌ error connecting to Watchtower: %v", err)
 	}
 	defer conn.Close()
 
 	client := pb.NewWatchtowerClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
-	file, err := os.Open(*targetFile)
+	var deps []manifest.Dependency
+	if *targetDir != "" {
+		fmt.Printf("🔍 analyzing %s (recursive) against Intelligence Database at %s...\n\n", *targetDir, *serverAddr)
+		deps, err = manifest.ScanDir(*targetDir)
+	} else {
+		fmt.Printf("🔍 analyzing %s against Intelligence Database at %s...\n\n", *targetFile, *serverAddr)
+		deps, err = manifest.ScanFile(*targetFile)
+	}
 	if err != nil {
-		log.Fatalf("❌ error reading file: %v", err)
+		log.Fatalf("❌ error reading manifest: %v", err)
 	}
-	defer file.Close()
 
-	fmt.Printf("🔍 analyzing %s against Intelligence Database at %s...\n\n", *targetFile, *serverAddr)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	scanner := bufio.NewScanner(file)
-	threatsFound := 0
-	scanned := 0
+	supportsBulk := false
+	if caps, err := client.Capabilities(ctx, &pb.CapabilitiesRequest{}); err != nil {
+		log.Printf("⚠️ capability negotiation failed, falling back to per-dependency checks: %v", err)
+	} else {
+		supportsBulk = caps.SupportsBulkCheckIoc
+	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	var results map[string]*pb.CheckResponse
+	if supportsBulk {
+		results, err = bulkCheck(ctx, client, deps)
+	} else {
+		results, err = unaryCheck(ctx, client, deps)
+	}
+	if err != nil {
+		log.Fatalf("❌ error checking dependencies: %v", err)
+	}
 
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
+	var findings []manifest.Finding
+	threatsFound := 0
+	for _, dep := range deps {
+		ref := dep.PackageRef()
+		resp, ok := results[ref]
+		if !ok {
+			fmt.Printf("⚠️ [SKIPPED] %s -> no response received\n", ref)
 			continue
 		}
+		if resp.Exists && resp.ActionBlock {
+			fmt.Printf("🚨 [BLOCKED] %s -> %s (Score: %d)\n", ref, resp.ThreatType, resp.ConfidenceScore)
+			threatsFound++
+			findings = append(findings, manifest.Finding{Dependency: dep, ThreatType: resp.ThreatType})
+		} else {
+			fmt.Printf("✅ [CLEAN] %s\n", ref)
+		}
+	}
+
+	if *sarifOut != "" {
+		if err := writeSARIFFile(*sarifOut, findings); err != nil {
+			log.Fatalf("❌ error writing SARIF output: %v", err)
+		}
+		fmt.Printf("📄 SARIF results written to %s\n", *sarifOut)
+	}
+
+	fmt.Println("------------------------------------------------")
+	if threatsFound > 0 {
+		fmt.Printf("❌ FAIL: %d malicious dependencies found.\n", threatsFound)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ SUCCESS: %d dependencies checked. No threats found.\n", len(deps))
+	os.Exit(0)
+}
+
+// writeSARIFFile renders findings as SARIF and writes them to path, for
+// uploading as GitHub code-scanning results.
+func writeSARIFFile(path string, findings []manifest.Finding) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
 
-		pkgName := parts[0]
-		if pkgName == "require" || pkgName == "module" || pkgName == "go" || pkgName == "//" {
-			if len(parts) > 2 && parts[0] == "require" {
-				pkgName = parts[1]
-			} else {
-				continue
+	return manifest.WriteSARIF(f, findings)
+}
+
+// bulkCheck opens a single BulkCheckIOC stream and pumps every dependency
+// over it, tagging each with a correlation ID so responses - which may
+// arrive out of order once the server batches lookups - can be matched back
+// to the dependency that triggered them.
+func bulkCheck(ctx context.Context, client pb.WatchtowerClient, deps []manifest.Dependency) (map[string]*pb.CheckResponse, error) {
+	stream, err := client.BulkCheckIOC(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bulk check stream: %w", err)
+	}
+
+	byCorrelationID := make(map[string]string, len(deps))
+	for i, dep := range deps {
+		byCorrelationID[strconv.Itoa(i)] = dep.PackageRef()
+	}
+
+	var sendErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, dep := range deps {
+			if err := stream.Send(&pb.CheckRequest{Value: dep.PackageRef(), CorrelationId: strconv.Itoa(i)}); err != nil {
+				sendErr = err
+				return
 			}
 		}
+		sendErr = stream.CloseSend()
+	}()
+
+	results := make(map[string]*pb.CheckResponse, len(deps))
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			wg.Wait()
+			return nil, fmt.Errorf("bulk check stream failed: %w", err)
+		}
+		if ref, ok := byCorrelationID[resp.CorrelationId]; ok {
+			results[ref] = resp
+		}
+	}
 
-		pkgName = strings.TrimSuffix(pkgName, "/v2")
+	wg.Wait()
+	if sendErr != nil {
+		return nil, fmt.Errorf("failed to send bulk check requests: %w", sendErr)
+	}
+
+	return results, nil
+}
 
-		scanned++
-		resp, err := client.CheckIOC(ctx, &pb.CheckRequest{Value: pkgName})
+// unaryCheck is the pre-streaming fallback: one CheckIOC RPC per dependency,
+// used when Capabilities reports an older server without BulkCheckIOC support.
+func unaryCheck(ctx context.Context, client pb.WatchtowerClient, deps []manifest.Dependency) (map[string]*pb.CheckResponse, error) {
+	results := make(map[string]*pb.CheckResponse, len(deps))
+	for _, dep := range deps {
+		ref := dep.PackageRef()
+		resp, err := client.CheckIOC(ctx, &pb.CheckRequest{Value: ref})
 		if err != nil {
-			log.Printf("⚠️ error checking %s: %v", pkgName, err)
+			log.Printf("⚠️ error checking %s: %v", ref, err)
 			continue
 		}
+		results[ref] = resp
+	}
+	return results, nil
+}
 
-		if resp.Exists && resp.ActionBlock {
-			fmt.Printf("🚨 [BLOCKED] %s -> %s (Score: %d)\n", pkgName, resp.ThreatType, resp.ConfidenceScore)
-			threatsFound++
-		} else {
-			fmt.Printf("✅ [CLEAN] %s\n", pkgName)
+// dialOptions builds the grpc.DialOption set for the requested auth flags:
+// transport credentials (mTLS if cert/key/ca are all set, TLS server-auth
+// only if just ca is set, insecure otherwise) plus per-RPC credentials
+// attaching the API key or OIDC token to every call, mirroring the schemes
+// security.AuthInterceptor accepts on the server side.
+func dialOptions(caFile, certFile, keyFile, apiKey, oidcToken string) ([]grpc.DialOption, error) {
+	transportCreds, secured, err := transportCredentials(caFile, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transportCreds)}
+
+	if apiKey != "" || oidcToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(scannerCredentials{
+			apiKey:         apiKey,
+			oidcToken:      oidcToken,
+			requireSecured: secured,
+		}))
+	}
+
+	return opts, nil
+}
+
+func transportCredentials(caFile, certFile, keyFile string) (creds credentials.TransportCredentials, secured bool, err error) {
+	if certFile != "" || keyFile != "" {
+		if caFile == "" || certFile == "" || keyFile == "" {
+			return nil, false, fmt.Errorf("-cert, -key and -ca must all be set together for mTLS")
+		}
+		clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		caPool, err := loadCAPool(caFile)
+		if err != nil {
+			return nil, false, err
 		}
+		return credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{clientCert},
+			RootCAs:      caPool,
+		}), true, nil
 	}
 
-	fmt.Println("------------------------------------------------")
-	if threatsFound > 0 {
-		fmt.Printf("❌ FAIL: %d malicious dependencies found.\n", threatsFound)
-		os.Exit(1)
+	if caFile != "" {
+		caPool, err := loadCAPool(caFile)
+		if err != nil {
+			return nil, false, err
+		}
+		return credentials.NewTLS(&tls.Config{RootCAs: caPool}), true, nil
 	}
 
-	fmt.Printf("✅ SUCCESS: %d dependencies checked. No threats found.\n", scanned)
-	os.Exit(0)
+	return insecure.NewCredentials(), false, nil
+}
+
+func loadCAPool(caFile string) (*x509.CertPool, error) {
+	caBytes, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caBytes) {
+		return nil, fmt.Errorf("no valid certificates found in %s", caFile)
+	}
+	return caPool, nil
+}
+
+// scannerCredentials implements credentials.PerRPCCredentials, attaching an
+// API key (x-api-key) or OIDC bearer token (authorization) to every call so
+// the caller doesn't have to thread metadata through each RPC site.
+type scannerCredentials struct {
+	apiKey         string
+	oidcToken      string
+	requireSecured bool
+}
+
+func (c scannerCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	md := map[string]string{}
+	if c.apiKey != "" {
+		md["x-api-key"] = c.apiKey
+	}
+	if c.oidcToken != "" {
+		md["authorization"] = "Bearer " + c.oidcToken
+	}
+	return md, nil
+}
+
+func (c scannerCredentials) RequireTransportSecurity() bool {
+	return c.requireSecured
 }
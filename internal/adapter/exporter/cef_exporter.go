@@ -3,6 +3,7 @@ package exporter
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -19,24 +20,27 @@ func NewCEFExporter(repo ports.IOCRepository) *CEFExporter {
 	return &CEFExporter{repo: repo}
 }
 
-// Export generates CEF-formatted IOC feed
+// ContentType satisfies the Exporter interface.
+func (e *CEFExporter) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Format satisfies the Exporter interface.
+func (e *CEFExporter) Format() string {
+	return "cef"
+}
+
+// ExportTo streams a CEF-formatted IOC feed to w, writing each line as rows
+// arrive from the repository instead of buffering the whole feed in memory.
+// limit caps the number of IOCs streamed, or 0 for no cap.
 // Format: CEF:Version|Device Vendor|Device Product|Device Version|Signature ID|Name|Severity|Extension
-func (e *CEFExporter) Export(ctx context.Context, since time.Time) (string, error) {
+func (e *CEFExporter) ExportTo(ctx context.Context, since time.Time, limit int, w io.Writer) error {
 	// Default to last 24 hours if no time specified
 	if since.IsZero() {
 		since = time.Now().Add(-24 * time.Hour)
 	}
 
-	// Fetch IOCs from database (limit to 10000 entries for performance)
-	iocs, err := e.repo.FindSince(ctx, since, 10000)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch IOCs: %w", err)
-	}
-
-	var output strings.Builder
-
-	// Convert domain IOCs to CEF entries
-	for _, ioc := range iocs {
+	err := e.repo.StreamSince(ctx, since, limit, func(ioc domain.IOC) error {
 		cefEntry := CEFEntry{
 			Value:      ioc.Value,
 			Type:       ioc.Type,
@@ -47,12 +51,14 @@ func (e *CEFExporter) Export(ctx context.Context, since time.Time) (string, erro
 			FirstSeen:  ioc.FirstSeen,
 		}
 
-		cefLine := e.formatCEF(cefEntry)
-		output.WriteString(cefLine)
-		output.WriteString("\n")
+		_, err := fmt.Fprintf(w, "%s\n", e.formatCEF(cefEntry))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream IOCs: %w", err)
 	}
 
-	return output.String(), nil
+	return nil
 }
 
 func (e *CEFExporter) formatCEF(ioc CEFEntry) string {
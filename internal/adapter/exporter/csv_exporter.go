@@ -0,0 +1,74 @@
+package exporter
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+)
+
+// CSVExporter exports IOCs as a flat CSV file, for direct use as a Sigma
+// "list" lookup or any SIEM lookup table that wants one row per indicator.
+type CSVExporter struct {
+	repo ports.IOCRepository
+}
+
+func NewCSVExporter(repo ports.IOCRepository) *CSVExporter {
+	return &CSVExporter{repo: repo}
+}
+
+// ContentType satisfies the Exporter interface.
+func (e *CSVExporter) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+// Format satisfies the Exporter interface.
+func (e *CSVExporter) Format() string {
+	return "csv"
+}
+
+var csvHeader = []string{"value", "type", "threat_type", "source", "tags", "confidence", "first_seen"}
+
+// ExportTo streams a CSV file to w, writing the header once and then one
+// row per IOC as rows arrive from the repository instead of buffering the
+// whole feed in memory. limit caps the number of IOCs streamed, or 0 for
+// no cap.
+func (e *CSVExporter) ExportTo(ctx context.Context, since time.Time, limit int, w io.Writer) error {
+	if since.IsZero() {
+		since = time.Now().Add(-24 * time.Hour)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := e.repo.StreamSince(ctx, since, limit, func(ioc domain.IOC) error {
+		row := []string{
+			ioc.Value,
+			string(ioc.Type),
+			ioc.ThreatType,
+			ioc.Source,
+			strings.Join(ioc.Tags, "|"),
+			strconv.Itoa(calculateConfidence(ioc)),
+			ioc.FirstSeen.UTC().Format(time.RFC3339),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream IOCs: %w", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,37 @@
+package exporter
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// metricsOnce ensures metrics are registered only once
+	metricsOnce sync.Once
+
+	// stixExportSizeBytes tracks the byte size of each STIX bundle ExportTo streams out
+	stixExportSizeBytes prometheus.Histogram
+)
+
+// InitMetrics registers all Prometheus metrics for the exporter package.
+// This should be called once at application startup.
+func InitMetrics() {
+	metricsOnce.Do(func() {
+		stixExportSizeBytes = promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_stix_export_size_bytes",
+				Help:    "Size in bytes of STIX bundles produced by STIXExporter.ExportTo",
+				Buckets: prometheus.ExponentialBuckets(1024, 4, 8), // 1KiB..16MiB
+			},
+		)
+	})
+}
+
+// RecordExportSize records the final byte size of one streamed STIX bundle.
+func RecordExportSize(bytes int) {
+	if stixExportSizeBytes != nil {
+		stixExportSizeBytes.Observe(float64(bytes))
+	}
+}
@@ -0,0 +1,73 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+)
+
+// NDJSONExporter exports IOCs as newline-delimited JSON, one object per
+// line, for SIEMs and scripts that want the raw IOC shape without a CEF/STIX
+// translation. It's the "json"/default format GetIOCFeed serves.
+type NDJSONExporter struct {
+	repo ports.IOCRepository
+}
+
+func NewNDJSONExporter(repo ports.IOCRepository) *NDJSONExporter {
+	return &NDJSONExporter{repo: repo}
+}
+
+// ContentType satisfies the Exporter interface.
+func (e *NDJSONExporter) ContentType() string {
+	return "application/x-ndjson; charset=utf-8"
+}
+
+// Format satisfies the Exporter interface.
+func (e *NDJSONExporter) Format() string {
+	return "ndjson"
+}
+
+type ndjsonRecord struct {
+	Value        string    `json:"value"`
+	Type         string    `json:"type"`
+	Source       string    `json:"source"`
+	ThreatType   string    `json:"threat_type"`
+	Tags         []string  `json:"tags,omitempty"`
+	Confidence   int       `json:"confidence"`
+	FirstSeen    time.Time `json:"first_seen"`
+	DateIngested time.Time `json:"date_ingested"`
+}
+
+// ExportTo streams one JSON object per line to w as rows arrive from the
+// repository instead of buffering the whole feed in memory. limit caps the
+// number of IOCs streamed, or 0 for no cap.
+func (e *NDJSONExporter) ExportTo(ctx context.Context, since time.Time, limit int, w io.Writer) error {
+	if since.IsZero() {
+		since = time.Now().Add(-24 * time.Hour)
+	}
+
+	encoder := json.NewEncoder(w)
+
+	err := e.repo.StreamSince(ctx, since, limit, func(ioc domain.IOC) error {
+		return encoder.Encode(ndjsonRecord{
+			Value:        ioc.Value,
+			Type:         string(ioc.Type),
+			Source:       ioc.Source,
+			ThreatType:   ioc.ThreatType,
+			Tags:         ioc.Tags,
+			Confidence:   calculateConfidence(ioc),
+			FirstSeen:    ioc.FirstSeen,
+			DateIngested: ioc.DateIngested,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream IOCs: %w", err)
+	}
+
+	return nil
+}
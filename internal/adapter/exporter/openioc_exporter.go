@@ -0,0 +1,134 @@
+package exporter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+)
+
+// OpenIOCExporter exports IOCs as OpenIOC 1.1 XML, for tools that consume
+// Mandiant-style indicator documents (e.g. Redline, FireEye appliances).
+type OpenIOCExporter struct {
+	repo ports.IOCRepository
+}
+
+func NewOpenIOCExporter(repo ports.IOCRepository) *OpenIOCExporter {
+	return &OpenIOCExporter{repo: repo}
+}
+
+// ContentType satisfies the Exporter interface.
+func (e *OpenIOCExporter) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+// Format satisfies the Exporter interface.
+func (e *OpenIOCExporter) Format() string {
+	return "openioc"
+}
+
+// ExportTo streams a single OpenIOC document with one indicator item per
+// IOC, writing each item as rows arrive from the repository instead of
+// buffering the whole feed in memory. limit caps the number of IOCs
+// streamed, or 0 for no cap.
+func (e *OpenIOCExporter) ExportTo(ctx context.Context, since time.Time, limit int, w io.Writer) error {
+	if since.IsZero() {
+		since = time.Now().Add(-24 * time.Hour)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	header := fmt.Sprintf(`<ioc xmlns="http://schemas.mandiant.com/2010/ioc" id="%s" last-modified="%s">`+
+		`<short_description>Watchtower threat intel export</short_description>`+
+		`<definition><Indicator operator="OR" id="%s">`,
+		uuid.New().String(), time.Now().UTC().Format(time.RFC3339), uuid.New().String())
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	err := e.repo.StreamSince(ctx, since, limit, func(ioc domain.IOC) error {
+		item := e.buildIndicatorItem(ioc)
+		return xml.NewEncoder(w).Encode(item)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream IOCs: %w", err)
+	}
+
+	_, err = io.WriteString(w, "</Indicator></definition></ioc>")
+	return err
+}
+
+// openIOCIndicatorItem mirrors OpenIOC 1.1's IndicatorItem element.
+type openIOCIndicatorItem struct {
+	XMLName   xml.Name `xml:"IndicatorItem"`
+	ID        string   `xml:"id,attr"`
+	Condition string   `xml:"condition,attr"`
+	Context   struct {
+		Document string `xml:"document,attr"`
+		Search   string `xml:"search,attr"`
+		Type     string `xml:"type,attr"`
+	} `xml:"Context"`
+	Content struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"Content"`
+}
+
+func (e *OpenIOCExporter) buildIndicatorItem(ioc domain.IOC) openIOCIndicatorItem {
+	item := openIOCIndicatorItem{
+		ID:        uuid.New().String(),
+		Condition: "is",
+	}
+	item.Context.Document, item.Context.Search, item.Context.Type = openIOCContext(ioc.Type)
+	item.Content.Type = openIOCContentType(ioc.Type, ioc.Value)
+	item.Content.Value = ioc.Value
+	return item
+}
+
+// openIOCContext maps a domain.IOCType to the OpenIOC document/search pair
+// that identifies what's being matched (e.g. a FileItem's Md5sum field).
+func openIOCContext(t domain.IOCType) (document, search, contextType string) {
+	switch t {
+	case domain.IPAddress:
+		return "Network", "Network/DNS", "mir"
+	case domain.Domain:
+		return "Network", "Network/DNS", "mir"
+	case domain.URL:
+		return "Network", "Network/URI", "mir"
+	case domain.FileHash:
+		return "FileItem", "FileItem/Md5sum", "mir"
+	case domain.FileName:
+		return "FileItem", "FileItem/FileName", "mir"
+	case domain.FilePath:
+		return "FileItem", "FileItem/FilePath", "mir"
+	case domain.Mutex:
+		return "ProcessItem", "ProcessItem/HandleList/Handle/Name", "mir"
+	case domain.Email:
+		return "Email", "Email/From", "mir"
+	default:
+		return "FileItem", "FileItem/FileName", "mir"
+	}
+}
+
+// openIOCContentType mirrors mispHashType/detectHashType for the FileItem
+// hash field OpenIOC expects (Md5sum is the element name regardless of the
+// actual algorithm, so the Content type attribute carries the real one).
+func openIOCContentType(t domain.IOCType, value string) string {
+	if t == domain.FileHash {
+		switch detectHashType(value) {
+		case "MD5":
+			return "md5"
+		case "SHA-1":
+			return "sha1"
+		default:
+			return "sha256"
+		}
+	}
+	return "string"
+}
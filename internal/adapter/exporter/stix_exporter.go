@@ -4,60 +4,138 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hive-corporation/watchtower/internal/core/domain"
 	"github.com/hive-corporation/watchtower/internal/core/ports"
+	"go.opentelemetry.io/otel"
 )
 
 // STIXExporter exports IOCs in STIX 2.1 format for SIEM ingestion
 type STIXExporter struct {
-	repo ports.IOCRepository
+	repo    ports.IOCRepository
+	scoring domain.ScoringConfig
 }
 
 func NewSTIXExporter(repo ports.IOCRepository) *STIXExporter {
-	return &STIXExporter{repo: repo}
+	return &STIXExporter{repo: repo, scoring: domain.DefaultScoringConfig()}
 }
 
-// Export generates STIX 2.1 formatted IOC feed
-func (e *STIXExporter) Export(ctx context.Context, since time.Time) (string, error) {
-	// Default to last 24 hours if no time specified
-	if since.IsZero() {
-		since = time.Now().Add(-24 * time.Hour)
-	}
+// ContentType satisfies the Exporter interface.
+func (e *STIXExporter) ContentType() string {
+	return "application/json; charset=utf-8"
+}
 
-	// Fetch IOCs from database (limit to 10000 entries for performance)
-	iocs, err := e.repo.FindSince(ctx, since, 10000)
+// Format satisfies the Exporter interface.
+func (e *STIXExporter) Format() string {
+	return "stix"
+}
+
+// FetchObjects fetches IOCs since the given watermark and converts them to
+// STIX indicator SDOs, without wrapping them in a bundle. It underlies the
+// TAXII objects/ endpoint, which needs the full page in memory to compute
+// Range/next pagination; ExportTo streams the equivalent bundle instead.
+func (e *STIXExporter) FetchObjects(ctx context.Context, since time.Time, limit int) ([]STIXObject, error) {
+	iocs, err := e.repo.FindSince(ctx, since, limit)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch IOCs: %w", err)
+		return nil, fmt.Errorf("failed to fetch IOCs: %w", err)
 	}
 
-	bundle := STIXBundle{
-		Type:        "bundle",
-		ID:          fmt.Sprintf("bundle--%s", uuid.New().String()),
-		SpecVersion: "2.1",
-		Objects:     []STIXObject{},
+	objects := make([]STIXObject, 0, len(iocs))
+	for _, ioc := range iocs {
+		objects = append(objects, e.convertToSTIX(ioc))
+	}
+	return objects, nil
+}
+
+// TAXIIEnvelopeItem pairs a converted STIX object with the IOC's
+// DateIngested, since STIXObject itself only carries STIX's own
+// created/modified timestamps and the TAXII objects/manifest endpoints need
+// DateIngested to report added_after filtering and the
+// X-TAXII-Date-Added-First/-Last headers.
+type TAXIIEnvelopeItem struct {
+	Object    STIXObject
+	DateAdded time.Time
+}
+
+// FetchEnvelopeItems is FetchObjects plus each IOC's DateIngested, for the
+// TAXII objects/ and manifest/ endpoints, which both need DateAdded
+// alongside (or instead of) the converted STIX object.
+func (e *STIXExporter) FetchEnvelopeItems(ctx context.Context, since time.Time, limit int) ([]TAXIIEnvelopeItem, error) {
+	iocs, err := e.repo.FindSince(ctx, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch IOCs: %w", err)
 	}
 
-	// Convert domain IOCs to STIX indicators
+	items := make([]TAXIIEnvelopeItem, 0, len(iocs))
 	for _, ioc := range iocs {
-		confidence := calculateConfidenceSTIX(ioc)
-		indicator := e.convertToSTIX(ioc, confidence)
-		bundle.Objects = append(bundle.Objects, indicator)
+		items = append(items, TAXIIEnvelopeItem{
+			Object:    e.convertToSTIX(ioc),
+			DateAdded: ioc.DateIngested,
+		})
+	}
+	return items, nil
+}
+
+// ExportTo streams a STIX 2.1 bundle to w: the bundle envelope is written up
+// front, then each indicator is marshaled and written to the objects array
+// as rows arrive from the repository, so a full-feed pull never has to hold
+// more than one indicator in memory at a time. limit caps the number of
+// IOCs streamed, or 0 for no cap.
+func (e *STIXExporter) ExportTo(ctx context.Context, since time.Time, limit int, w io.Writer) error {
+	ctx, span := otel.Tracer("watchtower/exporter").Start(ctx, "STIXExporter.Export")
+	defer span.End()
+
+	// Default to last 24 hours if no time specified
+	if since.IsZero() {
+		since = time.Now().Add(-24 * time.Hour)
+	}
+
+	counter := &countingWriter{w: w}
+	w = counter
+	defer func() { RecordExportSize(counter.n) }()
+
+	if _, err := fmt.Fprintf(w, `{"type":"bundle","id":"bundle--%s","spec_version":"2.1","objects":[`, uuid.New().String()); err != nil {
+		return err
 	}
 
-	// Marshal to JSON
-	jsonData, err := json.MarshalIndent(bundle, "", "  ")
+	first := true
+	err := e.repo.StreamSince(ctx, since, limit, func(ioc domain.IOC) error {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		object := e.convertToSTIX(ioc)
+		return json.NewEncoder(w).Encode(object)
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal STIX bundle: %w", err)
+		return fmt.Errorf("failed to stream IOCs: %w", err)
 	}
 
-	return string(jsonData), nil
+	_, err = io.WriteString(w, "]}")
+	return err
 }
 
-func (e *STIXExporter) convertToSTIX(ioc domain.IOC, confidence int) STIXObject {
+// countingWriter tallies bytes written through it, so ExportTo can record
+// the final bundle size without buffering it in memory first.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+func (e *STIXExporter) convertToSTIX(ioc domain.IOC) STIXObject {
 	now := time.Now().UTC()
 
 	// Build STIX pattern based on IOC type
@@ -84,13 +162,34 @@ func (e *STIXExporter) convertToSTIX(ioc domain.IOC, confidence int) STIXObject
 		Pattern:            pattern,
 		PatternType:        "stix",
 		ValidFrom:          ioc.FirstSeen.Format(time.RFC3339),
+		ValidUntil:         e.validUntil(ioc).Format(time.RFC3339),
 		IndicatorTypes:     indicatorTypes,
-		Confidence:         confidence,
+		Confidence:         ioc.Confidence,
 		Labels:             ioc.Tags,
 		ExternalReferences: externalRefs,
 	}
 }
 
+// validUntilHorizonHalfLives is how many decay half-lives past LastSeen a
+// value is considered stale enough to drop from consumers' active indicator
+// sets - 3 half-lives leaves ~12.5% of the original score, the point past
+// which CalculateConfidenceScore's own decay has already made it unlikely to
+// clear most alerting thresholds.
+const validUntilHorizonHalfLives = 3
+
+// validUntil derives STIX's valid_until from the IOC's own decay curve: a
+// botnet C2 indicator (7-day half-life) goes stale in about three weeks,
+// while a supply-chain package indicator (90-day half-life) stays valid for
+// most of a year, matching HalfLifeForThreatType's per-threat-type horizon.
+func (e *STIXExporter) validUntil(ioc domain.IOC) time.Time {
+	lastSeen := ioc.LastSeen
+	if lastSeen.IsZero() {
+		lastSeen = ioc.DateIngested
+	}
+	halfLife := domain.HalfLifeForThreatType(e.scoring, ioc.ThreatType)
+	return lastSeen.Add(time.Duration(halfLife*validUntilHorizonHalfLives*24) * time.Hour)
+}
+
 func (e *STIXExporter) buildPattern(ioc domain.IOC) string {
 	// Build STIX 2.1 pattern based on IOC type
 	switch ioc.Type {
@@ -182,6 +281,7 @@ type STIXObject struct {
 	Pattern            string              `json:"pattern"`
 	PatternType        string              `json:"pattern_type"`
 	ValidFrom          string              `json:"valid_from"`
+	ValidUntil         string              `json:"valid_until,omitempty"`
 	IndicatorTypes     []string            `json:"indicator_types"`
 	Confidence         int                 `json:"confidence"`
 	Labels             []string            `json:"labels,omitempty"`
@@ -192,30 +292,3 @@ type ExternalReference struct {
 	SourceName string `json:"source_name"`
 	URL        string `json:"url,omitempty"`
 }
-
-// calculateConfidenceSTIX generates a confidence score based on IOC attributes
-func calculateConfidenceSTIX(ioc domain.IOC) int {
-	confidence := 70 // Base confidence
-
-	// Increase confidence for certain sources
-	if ioc.Source == "alienvault-otx" || ioc.Source == "abusech-urlhaus" {
-		confidence += 10
-	}
-
-	// Increase confidence for certain threat types
-	if ioc.ThreatType == "malware_download" || ioc.ThreatType == "c2_server" {
-		confidence += 5
-	}
-
-	// Increase confidence if multiple tags
-	if len(ioc.Tags) > 3 {
-		confidence += 5
-	}
-
-	// Cap at 100
-	if confidence > 100 {
-		confidence = 100
-	}
-
-	return confidence
-}
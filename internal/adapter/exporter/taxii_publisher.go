@@ -0,0 +1,181 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+)
+
+// defaultMinPublishConfidence is the STIX confidence (domain.IOC.Confidence,
+// CalculateConfidenceScore's derived score) an IOC must meet before
+// TAXIIPublisher pushes it downstream, so partner TIPs only receive
+// Watchtower's high-confidence indicators rather than the full feed.
+const defaultMinPublishConfidence = 80
+
+// taxiiPublishMaxAttempts bounds the retries Publish applies to its POST,
+// mirroring TAXIIProvider's fetchPage backoff loop.
+const taxiiPublishMaxAttempts = 5
+
+// TAXIIPublisherConfig configures the downstream TAXII 2.1 collection
+// TAXIIPublisher pushes to, and the auth it authenticates with - the same
+// shape as TAXIIProviderConfig's auth fields, since a partner's inbound
+// collection is usually secured the same way their outbound feed is.
+type TAXIIPublisherConfig struct {
+	// CollectionURL is the objects/ endpoint to POST to, e.g.
+	// "https://partner.example.com/taxii2/api-root/collections/{id}/objects/".
+	CollectionURL string
+
+	BasicAuthUser string
+	BasicAuthPass string
+	BearerToken   string
+
+	// MinConfidence overrides defaultMinPublishConfidence; 0 keeps the default.
+	MinConfidence int
+}
+
+// TAXIIPublisher pushes Watchtower's own high-confidence IOCs, as a STIX 2.1
+// bundle, to a downstream TAXII 2.1 collection - the outbound counterpart to
+// TAXIIHandler, which serves our collection for clients that poll us
+// instead. It reuses STIXExporter for the IOC-to-STIX conversion so both
+// directions stay consistent.
+type TAXIIPublisher struct {
+	stix   *STIXExporter
+	client *http.Client
+	config TAXIIPublisherConfig
+}
+
+// NewTAXIIPublisher builds a TAXIIPublisher publishing stix's IOCs to
+// config.CollectionURL. When client is nil, one is built with a default
+// timeout.
+func NewTAXIIPublisher(client *http.Client, stix *STIXExporter, config TAXIIPublisherConfig) *TAXIIPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if config.MinConfidence == 0 {
+		config.MinConfidence = defaultMinPublishConfidence
+	}
+	return &TAXIIPublisher{stix: stix, client: client, config: config}
+}
+
+// Publish fetches IOCs ingested since the given watermark, keeps those at or
+// above config.MinConfidence, and POSTs them as a single STIX 2.1 bundle to
+// config.CollectionURL. It returns the latest "modified" timestamp among the
+// objects it fetched (not just the ones published), for the caller to
+// persist as the next watermark so a run of low-confidence IOCs doesn't
+// cause the same page to be re-fetched indefinitely. limit caps how many IOCs
+// are fetched; limit <= 0 fetches every IOC ingested since the watermark.
+func (p *TAXIIPublisher) Publish(ctx context.Context, since time.Time, limit int) (time.Time, error) {
+	var objects []STIXObject
+	var err error
+	if limit > 0 {
+		objects, err = p.stix.FetchObjects(ctx, since, limit)
+	} else {
+		err = p.stix.repo.StreamSince(ctx, since, 0, func(ioc domain.IOC) error {
+			objects = append(objects, p.stix.convertToSTIX(ioc))
+			return nil
+		})
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch IOCs to publish: %w", err)
+	}
+
+	latest := since
+	published := make([]STIXObject, 0, len(objects))
+	for _, obj := range objects {
+		if modified, err := time.Parse(time.RFC3339, obj.Modified); err == nil && modified.After(latest) {
+			latest = modified
+		}
+		if obj.Confidence >= p.config.MinConfidence {
+			published = append(published, obj)
+		}
+	}
+
+	if len(published) == 0 {
+		return latest, nil
+	}
+
+	bundle := STIXBundle{
+		Type:        "bundle",
+		ID:          fmt.Sprintf("bundle--%s", uuid.New().String()),
+		SpecVersion: "2.1",
+		Objects:     published,
+	}
+
+	body, err := json.Marshal(bundle)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to marshal STIX bundle: %w", err)
+	}
+
+	if err := p.postWithRetry(ctx, body); err != nil {
+		return time.Time{}, err
+	}
+	return latest, nil
+}
+
+// postWithRetry POSTs body to config.CollectionURL, retrying network
+// failures and 5xx responses with exponential backoff.
+func (p *TAXIIPublisher) postWithRetry(ctx context.Context, body []byte) error {
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = 1 * time.Second
+	expBackoff.MaxInterval = 30 * time.Second
+	expBackoff.Multiplier = 2.0
+
+	for attempt := 0; ; attempt++ {
+		transient, err := p.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !transient || attempt >= taxiiPublishMaxAttempts-1 {
+			return err
+		}
+
+		timer := time.NewTimer(expBackoff.NextBackOff())
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *TAXIIPublisher) post(ctx context.Context, body []byte) (transient bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.CollectionURL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to build TAXII publish request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/taxii+json;version=2.1;charset=utf-8")
+	req.Header.Set("Accept", "application/taxii+json;version=2.1")
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to publish STIX bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("TAXII collection returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return false, fmt.Errorf("TAXII collection returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+func (p *TAXIIPublisher) applyAuth(req *http.Request) {
+	if p.config.BasicAuthUser != "" && p.config.BasicAuthPass != "" {
+		req.SetBasicAuth(p.config.BasicAuthUser, p.config.BasicAuthPass)
+	}
+	if p.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.BearerToken)
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/hive-corporation/watchtower/internal/adapter/security"
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+)
+
+// defaultIPRateQPS/Burst bound a single caller IP before it has even
+// presented an API key, so one noisy anonymous client can't exhaust the
+// per-key limiter's work (or starve other callers) ahead of authentication.
+const (
+	defaultIPRateQPS   = 5.0
+	defaultIPRateBurst = 10
+)
+
+// scopedRoutes maps a public REST route to the scope required to call it.
+// Routes not listed here are left to AuthMiddleware's agent-based auth.
+var scopedRoutes = map[string]ports.APIKeyScope{
+	"/api/v1/iocs/check":           ports.ScopeReadIOC,
+	"/api/v1/iocs/search":          ports.ScopeReadIOC,
+	"/api/v1/iocs/feed":            ports.ScopeReadFeed,
+	"/api/v1/webhooks/sentinelone": ports.ScopeWriteWebhook,
+}
+
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the API key that authenticated the current
+// request, if any.
+func APIKeyFromContext(ctx context.Context) (ports.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(ports.APIKey)
+	return key, ok
+}
+
+// APIKeyMiddleware authenticates and rate-limits the handful of REST routes
+// meant to be reachable by third-party integrations outside the trusted
+// network (CheckIOC, SearchIOC, GetIOCFeed, SentinelOneWebhook). It runs
+// alongside, not instead of, AuthMiddleware: those four routes are carved
+// out of AuthMiddleware's agent auth via alwaysOpenPaths, and this
+// middleware enforces its own scoped bearer-token credentials (the
+// `api_keys` table) plus per-IP and per-key token-bucket rate limiting.
+// Routes it doesn't recognize pass straight through, since they're already
+// covered by AuthMiddleware's Agent-based auth.
+func APIKeyMiddleware(repo ports.APIKeyRepository) func(http.Handler) http.Handler {
+	ipLimiter := security.NewTokenBucketLimiter()
+	keyLimiter := security.NewTokenBucketLimiter()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requiredScope, scoped := scopeForRoute(r.URL.Path)
+			if !scoped {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowed, _ := ipLimiter.Allow(clientIP(r), defaultIPRateQPS, defaultIPRateBurst); !allowed {
+				rejectRateLimited(w)
+				return
+			}
+
+			if repo == nil {
+				writeError(w, http.StatusServiceUnavailable, "API key authentication is not configured")
+				return
+			}
+
+			apiKey, err := authenticateAPIKey(r, repo)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+
+			if !apiKey.HasScope(requiredScope) {
+				writeError(w, http.StatusForbidden, "API key does not have the required scope")
+				return
+			}
+
+			if allowed, _ := keyLimiter.Allow(apiKey.KeyID, apiKey.RateQPS, apiKey.RateBurst); !allowed {
+				rejectRateLimited(w)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), apiKeyContextKey{}, *apiKey)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func scopeForRoute(path string) (ports.APIKeyScope, bool) {
+	scope, ok := scopedRoutes[path]
+	return scope, ok
+}
+
+func authenticateAPIKey(r *http.Request, repo ports.APIKeyRepository) (*ports.APIKey, error) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, errUnauthenticated("missing bearer token")
+	}
+	rawKey := strings.TrimPrefix(header, prefix)
+
+	key, err := repo.FindByHash(r.Context(), HashAPIKey(rawKey))
+	if err != nil {
+		return nil, errUnauthenticated("failed to resolve API key")
+	}
+	if key == nil {
+		return nil, errUnauthenticated("invalid API key")
+	}
+	if key.Revoked() {
+		return nil, errUnauthenticated("API key revoked")
+	}
+	return key, nil
+}
+
+// clientIP extracts the request's remote address without its port, falling
+// back to the raw value if it isn't in host:port form.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rejectRateLimited writes a 429 with a conservative fixed Retry-After. The
+// token bucket refills continuously rather than on a fixed schedule, so
+// there's no single "retry at" instant to compute exactly; 1 second is a
+// safe lower bound for qps >= 1 limiters.
+func rejectRateLimited(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+}
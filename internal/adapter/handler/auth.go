@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+)
+
+// AuthMode selects how AuthMiddleware authenticates a caller. It is
+// selectable per-listener so a webhook ingress, a bouncer-facing ingress, and
+// a local/dev deployment can each pick the mode that fits.
+type AuthMode string
+
+const (
+	// AuthModeNone disables authentication entirely. Only ever appropriate
+	// for local development — never the implicit default.
+	AuthModeNone AuthMode = "none"
+	// AuthModeAPIKey requires a Bearer token matching an enrolled agent's
+	// hashed API key.
+	AuthModeAPIKey AuthMode = "api_key"
+	// AuthModeMTLS requires the caller's verified leaf certificate
+	// fingerprint to match an enrolled agent.
+	AuthModeMTLS AuthMode = "mtls"
+)
+
+type agentContextKey struct{}
+
+// AgentFromContext returns the enrolled agent attached to the request
+// context by AuthMiddleware, if any.
+func AgentFromContext(ctx context.Context) (ports.Agent, bool) {
+	agent, ok := ctx.Value(agentContextKey{}).(ports.Agent)
+	return agent, ok
+}
+
+// HashAPIKey returns the SHA-256 hex digest stored for an agent's API key.
+// Raw keys are never persisted or logged.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// routeRoles restricts which AgentRole may call which route prefix. Routes
+// not listed here are open to any authenticated agent (but still require
+// authentication unless mode is AuthModeNone).
+var routeRoles = map[string]ports.AgentRole{
+	"/api/v1/webhooks/":        ports.AgentRoleWebhook,
+	"/api/v1/decisions/stream": ports.AgentRoleBouncer,
+}
+
+// alwaysOpenPaths never require authentication regardless of mode. The four
+// public-facing routes are exempted here because APIKeyMiddleware gates them
+// instead, with its own scoped-API-key credentials meant for third-party
+// integrations outside the trusted network rather than enrolled Agents.
+var alwaysOpenPaths = map[string]bool{
+	"/api/v1/health":               true,
+	"/api/v1/agents/enroll":        true,
+	"/api/v1/iocs/check":           true,
+	"/api/v1/iocs/search":          true,
+	"/api/v1/iocs/feed":            true,
+	"/api/v1/webhooks/sentinelone": true,
+}
+
+// AuthMiddleware replaces a single shared bearer token with per-agent
+// enrollment: it resolves the caller's identity via the configured mode and
+// enforces role-based access per route. Unlike the previous implementation,
+// it fails closed — an unset or unsupported mode denies every request rather
+// than silently disabling auth.
+func AuthMiddleware(mode AuthMode, repo ports.AgentRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if alwaysOpenPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if mode == AuthModeNone {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			agent, err := authenticate(r, mode, repo)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+
+			if agent.Revoked() {
+				writeError(w, http.StatusUnauthorized, "credentials revoked")
+				return
+			}
+
+			if requiredRole, ok := routeForRole(r.URL.Path); ok && agent.Role != requiredRole && agent.Role != ports.AgentRoleAdmin {
+				writeError(w, http.StatusForbidden, "agent role does not permit this route")
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), agentContextKey{}, *agent)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func routeForRole(path string) (ports.AgentRole, bool) {
+	for prefix, role := range routeRoles {
+		if strings.HasPrefix(path, prefix) {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+func authenticate(r *http.Request, mode AuthMode, repo ports.AgentRepository) (*ports.Agent, error) {
+	if repo == nil {
+		return nil, errUnauthenticated("no agent repository configured")
+	}
+
+	switch mode {
+	case AuthModeAPIKey:
+		header := r.Header.Get("Authorization")
+		const prefix = "Bearer "
+		if !strings.HasPrefix(header, prefix) {
+			return nil, errUnauthenticated("missing bearer token")
+		}
+		key := strings.TrimPrefix(header, prefix)
+
+		agent, err := repo.FindByAPIKeyHash(r.Context(), HashAPIKey(key))
+		if err != nil {
+			return nil, errUnauthenticated("failed to resolve agent")
+		}
+		if agent == nil {
+			return nil, errUnauthenticated("invalid API key")
+		}
+		return agent, nil
+
+	case AuthModeMTLS:
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return nil, errUnauthenticated("client certificate required")
+		}
+		sum := sha256.Sum256(r.TLS.PeerCertificates[0].Raw)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		agent, err := repo.FindByCertFingerprint(r.Context(), fingerprint)
+		if err != nil {
+			return nil, errUnauthenticated("failed to resolve agent")
+		}
+		if agent == nil {
+			return nil, errUnauthenticated("unrecognized client certificate")
+		}
+		return agent, nil
+
+	default:
+		return nil, errUnauthenticated("unsupported auth mode")
+	}
+}
+
+type errUnauthenticated string
+
+func (e errUnauthenticated) Error() string { return string(e) }
+
+// compareAPIKeyHash does a constant-time comparison of two hex digests, kept
+// around for callers that already hold both hashes (e.g. the enrollment flow).
+func compareAPIKeyHash(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// enrollRequest is the payload for POST /api/v1/agents/enroll.
+type enrollRequest struct {
+	BootstrapToken string `json:"bootstrap_token"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+}
+
+type enrollResponse struct {
+	AgentID string `json:"agent_id"`
+	APIKey  string `json:"api_key"`
+}
+
+// EnrollAgent mints a per-agent API key in exchange for a shared bootstrap
+// token (configured via AGENT_BOOTSTRAP_TOKEN), replacing ad-hoc manual key
+// distribution. The raw key is returned exactly once and only its hash is
+// persisted.
+func (h *RestHandler) EnrollAgent(w http.ResponseWriter, r *http.Request) {
+	if h.agentRepo == nil {
+		writeError(w, http.StatusServiceUnavailable, "agent enrollment is not configured")
+		return
+	}
+
+	var req enrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	if h.bootstrapToken == "" || !compareAPIKeyHash(HashAPIKey(req.BootstrapToken), HashAPIKey(h.bootstrapToken)) {
+		writeError(w, http.StatusUnauthorized, "invalid bootstrap token")
+		return
+	}
+
+	role := ports.AgentRole(req.Role)
+	switch role {
+	case ports.AgentRoleWebhook, ports.AgentRoleBouncer, ports.AgentRoleAdmin:
+	default:
+		writeError(w, http.StatusBadRequest, "role must be one of webhook, bouncer, admin")
+		return
+	}
+
+	apiKey := uuid.New().String()
+	agent := ports.Agent{
+		AgentID:      uuid.New().String(),
+		Name:         req.Name,
+		Role:         role,
+		HashedAPIKey: HashAPIKey(apiKey),
+		EnrolledAt:   time.Now().UTC(),
+	}
+
+	if err := h.agentRepo.Create(r.Context(), agent); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to enroll agent")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, enrollResponse{AgentID: agent.AgentID, APIKey: apiKey})
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+)
+
+// decisionsSequence is the process-wide counter behind each cursor's
+// sequence number; every pull takes the next value.
+var decisionsSequence int64
+
+// CursorHeader is the response header carrying the opaque cursor a caller
+// must send back on its next poll.
+const CursorHeader = "X-Watchtower-Cursor"
+
+// decisionsCursor is the opaque state encoded into the cursor token: the
+// watermark timestamp of the last successful pull plus a monotonic sequence
+// number, so two pulls issued in the same instant still order deterministically.
+type decisionsCursor struct {
+	lastPullAt time.Time
+	sequence   int64
+}
+
+func encodeCursor(c decisionsCursor) string {
+	raw := fmt.Sprintf("%d:%d", c.lastPullAt.UnixNano(), c.sequence)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(token string) (decisionsCursor, error) {
+	if token == "" {
+		return decisionsCursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return decisionsCursor{}, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return decisionsCursor{}, fmt.Errorf("malformed cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return decisionsCursor{}, fmt.Errorf("malformed cursor timestamp: %w", err)
+	}
+
+	seq, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return decisionsCursor{}, fmt.Errorf("malformed cursor sequence: %w", err)
+	}
+
+	return decisionsCursor{lastPullAt: time.Unix(0, nanos), sequence: seq}, nil
+}
+
+// decisionStreamIOC is the wire shape for a single entry in the decisions
+// stream response, modeled on CrowdSec LAPI's decisions/stream payload.
+type decisionStreamIOC struct {
+	Value      string   `json:"value"`
+	Type       string   `json:"type"`
+	Source     string   `json:"source"`
+	ThreatType string   `json:"threat_type"`
+	Tags       []string `json:"tags"`
+}
+
+func toDecisionStreamIOC(ioc domain.IOC) decisionStreamIOC {
+	return decisionStreamIOC{
+		Value:      ioc.Value,
+		Type:       string(ioc.Type),
+		Source:     ioc.Source,
+		ThreatType: ioc.ThreatType,
+		Tags:       ioc.Tags,
+	}
+}
+
+// DecisionsStream serves an incremental feed of IOC additions/removals so
+// downstream remediation components (firewalls, EDR agents, DNS sinkholes)
+// can sync state without re-downloading the whole feed on every poll.
+//
+// On startup=true it returns the full current set as {new, deleted: []}.
+// On subsequent calls it returns only the IOCs added or removed since the
+// caller's cursor, passed back via ?cursor= or the request's ETag-style
+// If-None-Match header, and returns the next cursor in X-Watchtower-Cursor.
+func (h *RestHandler) DecisionsStream(w http.ResponseWriter, r *http.Request) {
+	bouncer := r.URL.Query().Get("bouncer")
+	if bouncer == "" {
+		bouncer = "anonymous"
+	}
+	RecordDecisionsStreamPull(bouncer)
+
+	var scopes []domain.IOCType
+	if raw := r.URL.Query().Get("scopes"); raw != "" {
+		for _, s := range strings.Split(raw, ",") {
+			scopes = append(scopes, domain.IOCType(strings.TrimSpace(s)))
+		}
+	}
+
+	startup := r.URL.Query().Get("startup") == "true"
+
+	cursorToken := r.URL.Query().Get("cursor")
+	if cursorToken == "" {
+		cursorToken = r.Header.Get("If-None-Match")
+	}
+
+	var since time.Time
+	if !startup {
+		cursor, err := decodeCursor(cursorToken)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor: "+err.Error())
+			return
+		}
+		since = cursor.lastPullAt
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	added, removed, err := h.repo.FindChangesSince(ctx, since, scopes)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to compute decisions stream")
+		return
+	}
+
+	newEntries := make([]decisionStreamIOC, 0, len(added))
+	for _, ioc := range added {
+		newEntries = append(newEntries, toDecisionStreamIOC(ioc))
+	}
+
+	deletedEntries := make([]decisionStreamIOC, 0, len(removed))
+	for _, ioc := range removed {
+		deletedEntries = append(deletedEntries, toDecisionStreamIOC(ioc))
+	}
+
+	nextCursor := encodeCursor(decisionsCursor{lastPullAt: time.Now().UTC(), sequence: atomic.AddInt64(&decisionsSequence, 1)})
+	w.Header().Set(CursorHeader, nextCursor)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"new":     newEntries,
+		"deleted": deletedEntries,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"strings"
 	"time"
@@ -17,12 +18,14 @@ import (
 
 type GrpcServer struct {
 	pb.UnimplementedWatchtowerServer
-	repo ports.IOCRepository
+	repo    ports.IOCRepository
+	scoring domain.ScoringConfig
 }
 
-func NewGrpcServer(repo ports.IOCRepository) *GrpcServer {
+func NewGrpcServer(repo ports.IOCRepository, scoring domain.ScoringConfig) *GrpcServer {
 	return &GrpcServer{
-		repo: repo,
+		repo:    repo,
+		scoring: scoring,
 	}
 }
 
@@ -31,21 +34,46 @@ func (s *GrpcServer) CheckIOC(ctx context.Context, req *pb.CheckRequest) (*pb.Ch
 		return nil, errors.New("value cannot be empty")
 	}
 
-	ioc, err := s.repo.FindByValue(ctx, req.Value)
-
+	resp, err := s.lookupCheckResponse(ctx, req.Value)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			// Não encontrou = Não é ameaça (por enquanto)
-			return &pb.CheckResponse{
-				Exists:      false,
-				ActionBlock: false,
-			}, nil
-		}
 		log.Printf("❌ error checking IOC: %v", err)
 		return nil, err
 	}
 
-	// 2. Encontrou! Mapeia para a resposta gRPC
+	return resp, nil
+}
+
+// lookupCheckResponse resolves a single check value, which may be a bare IOC
+// value or a "package@version" pair (the manifest scanners emit the latter),
+// reusing the same parsePackageVersion split SearchIOC relies on so a pinned
+// dependency version is actually matched instead of silently ignored.
+func (s *GrpcServer) lookupCheckResponse(ctx context.Context, value string) (*pb.CheckResponse, error) {
+	pkgName, version := parsePackageVersion(value)
+
+	var ioc *domain.IOC
+	if version != "" {
+		iocs, err := s.repo.FindByValueAndVersion(ctx, pkgName, version)
+		if err != nil {
+			return nil, err
+		}
+		if len(iocs) > 0 {
+			ioc = &iocs[0]
+		}
+	} else {
+		found, err := s.repo.FindByValue(ctx, pkgName)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return &pb.CheckResponse{Exists: false, ActionBlock: false}, nil
+			}
+			return nil, err
+		}
+		ioc = found
+	}
+
+	if ioc == nil {
+		return &pb.CheckResponse{Exists: false, ActionBlock: false}, nil
+	}
+
 	return &pb.CheckResponse{
 		Exists:          true,
 		ThreatType:      ioc.ThreatType,
@@ -89,19 +117,165 @@ func (s *GrpcServer) SearchIOC(ctx context.Context, req *pb.SearchRequest) (*pb.
 	}
 
 	// 4. Aggregate data from all IOCs
+	score, rationale := domain.CalculateConfidenceScore(iocs, s.scoring)
 	details := &pb.IOCDetails{
-		Value:        req.Value,
-		Type:         string(iocs[0].Type), // Use type from first IOC
-		OverallScore: domain.CalculateConfidenceScore(iocs),
-		AllTags:      collectUniqueTags(iocs),
-		FirstSeen:    timestamppb.New(findEarliestTimestamp(iocs)),
-		LastSeen:     timestamppb.New(findLatestTimestamp(iocs)),
-		Sightings:    buildSightings(iocs),
+		Value:          req.Value,
+		Type:           string(iocs[0].Type), // Use type from first IOC
+		OverallScore:   score,
+		ScoreRationale: rationale,
+		AllTags:        collectUniqueTags(iocs),
+		FirstSeen:      timestamppb.New(findEarliestTimestamp(iocs)),
+		LastSeen:       timestamppb.New(findLatestTimestamp(iocs)),
+		Sightings:      buildSightings(iocs),
 	}
 
 	return details, nil
 }
 
+// bulkCheckBatchSize caps how many pending CheckRequests BulkCheckIOC
+// coalesces into a single FindByValuesIn query. Flushing at this size bounds
+// per-batch memory and keeps any one query from holding the stream's
+// responses back too long, while still collapsing most manifest scans (which
+// run well under this many dependencies) into one or two round trips.
+const bulkCheckBatchSize = 200
+
+// Capabilities advertises which streaming RPCs this server supports, so
+// clients built against a newer proto (e.g. one expecting BulkCheckIOC) can
+// fall back to the unary CheckIOC when talking to an older deployment that
+// hasn't rolled out streaming support yet.
+func (s *GrpcServer) Capabilities(ctx context.Context, req *pb.CapabilitiesRequest) (*pb.CapabilitiesResponse, error) {
+	return &pb.CapabilitiesResponse{
+		SupportsBulkCheckIoc: true,
+	}, nil
+}
+
+// BulkCheckIOC is a bidirectional streaming counterpart to CheckIOC for
+// high-throughput manifest scans: instead of one unary RPC per dependency
+// (which serializes latency and repeats TLS/context overhead), the client
+// pumps every dependency over a single stream and the server coalesces them
+// into batched FindByValuesIn lookups. Responses carry the request's
+// CorrelationId back so the client can match them regardless of arrival
+// order, since batching means results don't necessarily come back in the
+// order they were sent.
+func (s *GrpcServer) BulkCheckIOC(stream pb.Watchtower_BulkCheckIOCServer) error {
+	ctx := stream.Context()
+	batch := make([]*pb.CheckRequest, 0, bulkCheckBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		responses, err := s.resolveBulkBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, resp := range responses {
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return flush()
+		}
+		if err != nil {
+			return err
+		}
+
+		if req.Value == "" {
+			if err := stream.Send(&pb.CheckResponse{
+				CorrelationId: req.CorrelationId,
+				Exists:        false,
+				ActionBlock:   false,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		batch = append(batch, req)
+		if len(batch) >= bulkCheckBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolveBulkBatch runs one FindByValuesIn query for the bare (unversioned)
+// requests in batch and maps the results back onto each request's
+// CorrelationId, so a value with no match still gets an Exists: false
+// response rather than being silently dropped from the stream. Requests
+// carrying a "package@version" value bypass the batched query - FindByValuesIn
+// is an exact-match lookup and can't apply per-item version matching - and
+// are resolved individually via the same version-aware path CheckIOC uses.
+func (s *GrpcServer) resolveBulkBatch(ctx context.Context, batch []*pb.CheckRequest) ([]*pb.CheckResponse, error) {
+	var bare, versioned []*pb.CheckRequest
+	for _, req := range batch {
+		if _, version := parsePackageVersion(req.Value); version != "" {
+			versioned = append(versioned, req)
+		} else {
+			bare = append(bare, req)
+		}
+	}
+
+	responses := make([]*pb.CheckResponse, 0, len(batch))
+
+	if len(bare) > 0 {
+		values := make([]string, len(bare))
+		for i, req := range bare {
+			values[i] = req.Value
+		}
+
+		iocs, err := s.repo.FindByValuesIn(ctx, values)
+		if err != nil {
+			log.Printf("❌ error bulk checking IOCs: %v", err)
+			return nil, err
+		}
+
+		found := make(map[string]domain.IOC, len(iocs))
+		for _, ioc := range iocs {
+			found[ioc.Value] = ioc
+		}
+
+		for _, req := range bare {
+			ioc, ok := found[req.Value]
+			if !ok {
+				responses = append(responses, &pb.CheckResponse{
+					CorrelationId: req.CorrelationId,
+					Exists:        false,
+					ActionBlock:   false,
+				})
+				continue
+			}
+			responses = append(responses, &pb.CheckResponse{
+				CorrelationId:   req.CorrelationId,
+				Exists:          true,
+				ThreatType:      ioc.ThreatType,
+				ConfidenceScore: 80,
+				ActionBlock:     true,
+			})
+		}
+	}
+
+	for _, req := range versioned {
+		resp, err := s.lookupCheckResponse(ctx, req.Value)
+		if err != nil {
+			log.Printf("❌ error bulk checking versioned IOC %s: %v", req.Value, err)
+			return nil, err
+		}
+		resp.CorrelationId = req.CorrelationId
+		responses = append(responses, resp)
+	}
+
+	return responses, nil
+}
+
 // collectUniqueTags aggregates all unique tags from multiple IOCs
 func collectUniqueTags(iocs []domain.IOC) []string {
 	tagSet := make(map[string]bool)
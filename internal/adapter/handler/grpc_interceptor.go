@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsUnaryInterceptor times every unary RPC and records it against
+// grpcRequestDuration, labeled by method and status code. It's independent
+// of security.AuthInterceptor - wire both into grpcServerOptions via
+// grpc.ChainUnaryInterceptor so auth failures still get timed.
+func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		RecordGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start))
+		return resp, err
+	}
+}
@@ -0,0 +1,20 @@
+package handler
+
+import "go.uber.org/zap"
+
+// handlerLogger is the zap.Logger RestHandler methods and this package's
+// helpers log through, in the same package-level-singleton style as
+// InitMetrics's metric vars. It defaults to a no-op logger so callers that
+// build a RestHandler without SetLogger - every e2e test, and any binary
+// that doesn't call it - keep working exactly as before; cmd/watchtower-api
+// overrides it once at startup with a real logger from
+// observability.NewLogger.
+var handlerLogger = zap.NewNop()
+
+// SetLogger replaces handlerLogger. Passing nil is a no-op, so callers can
+// always pass whatever observability.NewLogger returned without a nil check.
+func SetLogger(logger *zap.Logger) {
+	if logger != nil {
+		handlerLogger = logger
+	}
+}
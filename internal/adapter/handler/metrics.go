@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// metricsOnce ensures metrics are registered only once
+	metricsOnce sync.Once
+
+	// httpPanicsTotal tracks panics recovered by the HTTP middleware chain
+	httpPanicsTotal *prometheus.CounterVec
+
+	// decisionsStreamPullsTotal tracks decisions-stream polls per bouncer
+	decisionsStreamPullsTotal *prometheus.CounterVec
+
+	// grpcRequestDuration tracks gRPC unary RPC latency by method and status code
+	grpcRequestDuration *prometheus.HistogramVec
+
+	// restRequestsTotal is the REST RED metric's "rate"/"errors" component:
+	// every REST request, by route template, method and status code.
+	restRequestsTotal *prometheus.CounterVec
+
+	// restRequestDuration is the REST RED metric's "duration" component.
+	restRequestDuration *prometheus.HistogramVec
+
+	// webhookProcessingDuration tracks end-to-end receiveWebhook/processAlert
+	// latency, by source adapter and outcome, separate from restRequestDuration
+	// since a webhook's cost (enrichment + LLM triage + notification) isn't
+	// representative of the rest of the REST surface.
+	webhookProcessingDuration *prometheus.HistogramVec
+)
+
+// InitMetrics registers all Prometheus metrics for the REST handler
+// This should be called once at application startup
+func InitMetrics() {
+	metricsOnce.Do(func() {
+		httpPanicsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "watchtower_http_panics_total",
+				Help: "Total number of HTTP handler panics recovered, by path and method",
+			},
+			[]string{"path", "method"},
+		)
+
+		decisionsStreamPullsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "watchtower_decisions_stream_pulls_total",
+				Help: "Total number of decisions-stream polls, by bouncer",
+			},
+			[]string{"bouncer"},
+		)
+
+		grpcRequestDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_grpc_request_duration_seconds",
+				Help:    "Duration of gRPC unary RPCs, by method and status code",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"method", "code"},
+		)
+
+		restRequestsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "watchtower_rest_requests_total",
+				Help: "Total number of REST API requests, by route, method and status code",
+			},
+			[]string{"route", "method", "code"},
+		)
+
+		restRequestDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_rest_request_duration_seconds",
+				Help:    "Duration of REST API requests, by route, method and status code",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"route", "method", "code"},
+		)
+
+		webhookProcessingDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_webhook_processing_duration_seconds",
+				Help:    "End-to-end duration of receiveWebhook/processAlert, by source adapter and outcome",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"source", "outcome"},
+		)
+	})
+}
+
+// RecordPanic records a recovered panic for the given route
+func RecordPanic(path, method string) {
+	if httpPanicsTotal != nil {
+		httpPanicsTotal.WithLabelValues(path, method).Inc()
+	}
+}
+
+// RecordDecisionsStreamPull records a decisions-stream poll from a bouncer
+func RecordDecisionsStreamPull(bouncer string) {
+	if decisionsStreamPullsTotal != nil {
+		decisionsStreamPullsTotal.WithLabelValues(bouncer).Inc()
+	}
+}
+
+// RecordGRPCRequest records one unary RPC's latency, labeled by method and
+// status code (see MetricsUnaryInterceptor).
+func RecordGRPCRequest(method, code string, duration time.Duration) {
+	if grpcRequestDuration != nil {
+		grpcRequestDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+	}
+}
+
+// RecordRESTRequest records one REST API request's outcome and latency (see
+// MetricsMiddleware).
+func RecordRESTRequest(route, method, code string, duration time.Duration) {
+	if restRequestsTotal != nil {
+		restRequestsTotal.WithLabelValues(route, method, code).Inc()
+	}
+	if restRequestDuration != nil {
+		restRequestDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+	}
+}
+
+// RecordWebhookProcessing records one receiveWebhook/processAlert call's
+// end-to-end latency. outcome is "ok", "unauthorized", or "bad_request".
+func RecordWebhookProcessing(source, outcome string, duration time.Duration) {
+	if webhookProcessingDuration != nil {
+		webhookProcessingDuration.WithLabelValues(source, outcome).Observe(duration.Seconds())
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// RecoveryMiddleware recovers from panics in the wrapped handler, logs the
+// stack trace together with the request that triggered it, records a
+// watchtower_http_panics_total increment, and responds with a JSON 500
+// instead of letting net/http drop the connection silently.
+//
+// It should be registered as the outermost middleware (before logging/auth)
+// so that it also catches panics raised by middleware further down the chain.
+func RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("❌ panic recovered in %s %s (remote=%s): %v\n%s",
+					r.Method, r.URL.Path, r.RemoteAddr, rec, debug.Stack())
+				RecordPanic(r.URL.Path, r.Method)
+				writeJSON(w, http.StatusInternalServerError, map[string]string{
+					"error": "internal server error",
+				})
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code a
+// handler wrote, for MetricsMiddleware - net/http gives no other way to
+// observe it after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// MetricsMiddleware records watchtower_rest_requests_total and
+// watchtower_rest_request_duration_seconds (the REST RED metric's
+// rate/errors/duration) for every request, labeled by mux's route template
+// (e.g. "/api/v1/iocs/check") rather than the raw path, so path parameters
+// like a TAXII collection ID don't blow up cardinality.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := r.URL.Path
+		if current := mux.CurrentRoute(r); current != nil {
+			if tmpl, err := current.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+		RecordRESTRequest(route, r.Method, strconv.Itoa(rec.status), time.Since(start))
+	})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoveryMiddleware_RecoversPanic(t *testing.T) {
+	InitMetrics()
+
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/iocs/check", nil)
+	rec := httptest.NewRecorder()
+
+	RecoveryMiddleware(panicking).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+}
+
+func TestRecoveryMiddleware_PassesThroughWithoutPanic(t *testing.T) {
+	InitMetrics()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	rec := httptest.NewRecorder()
+
+	RecoveryMiddleware(ok).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestRecordPanic_Metric(t *testing.T) {
+	InitMetrics()
+
+	// Should not panic, and should be safe to call repeatedly
+	RecordPanic("/api/v1/iocs/check", http.MethodGet)
+	RecordPanic("/api/v1/iocs/search", http.MethodGet)
+}
@@ -1,37 +1,76 @@
 package handler
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hive-corporation/watchtower/internal/adapter/exporter"
 	"github.com/hive-corporation/watchtower/internal/adapter/llm"
 	"github.com/hive-corporation/watchtower/internal/adapter/notifier"
+	"github.com/hive-corporation/watchtower/internal/adapter/webhook"
 	"github.com/hive-corporation/watchtower/internal/core/domain"
 	"github.com/hive-corporation/watchtower/internal/core/ports"
+	"go.uber.org/zap"
 )
 
 type RestHandler struct {
-	repo          ports.IOCRepository
-	slackNotifier *notifier.SlackNotifier
-	cefExporter   *exporter.CEFExporter
-	stixExporter  *exporter.STIXExporter
-	llmTriager    *llm.LLMTriager
+	repo               ports.IOCRepository
+	notifier           notifier.Notifier
+	cefExporter        *exporter.CEFExporter
+	stixExporter       *exporter.STIXExporter
+	exporters          *exporter.Registry
+	llmTriager         *llm.LLMTriager
+	agentRepo          ports.AgentRepository
+	bootstrapToken     string
+	sentinelOneAdapter *webhook.SentinelOneAdapter
+	crowdStrikeAdapter *webhook.CrowdStrikeAdapter
+	genericAdapter     *webhook.GenericAdapter
 }
 
-func NewRestHandler(repo ports.IOCRepository, slackNotifier *notifier.SlackNotifier, llmTriager *llm.LLMTriager) *RestHandler {
+// NewRestHandler wires repo, notif (a SlackNotifier, MultiNotifier, Router,
+// or any other notifier.Notifier - nil disables notifications), and
+// llmTriager into a handler ready to register against a mux.Router.
+func NewRestHandler(repo ports.IOCRepository, notif notifier.Notifier, llmTriager *llm.LLMTriager) *RestHandler {
+	cefExporter := exporter.NewCEFExporter(repo)
+	stixExporter := exporter.NewSTIXExporter(repo)
+
+	registry := exporter.NewRegistry()
+	registry.Register("cef", cefExporter)
+	registry.Register("stix", stixExporter)
+	registry.Register("misp", exporter.NewMISPExporter(repo))
+	registry.Register("openioc", exporter.NewOpenIOCExporter(repo))
+	registry.Register("csv", exporter.NewCSVExporter(repo))
+	registry.Register("ndjson", exporter.NewNDJSONExporter(repo))
+
 	return &RestHandler{
-		repo:          repo,
-		slackNotifier: slackNotifier,
-		cefExporter:   exporter.NewCEFExporter(repo),
-		stixExporter:  exporter.NewSTIXExporter(repo),
-		llmTriager:    llmTriager,
+		repo:               repo,
+		notifier:           notif,
+		cefExporter:        cefExporter,
+		stixExporter:       stixExporter,
+		exporters:          registry,
+		llmTriager:         llmTriager,
+		sentinelOneAdapter: webhook.NewSentinelOneAdapter(),
+		crowdStrikeAdapter: webhook.NewCrowdStrikeAdapter(),
+		genericAdapter:     webhook.NewGenericAdapter(),
 	}
 }
 
+// NewRestHandlerWithAgents is NewRestHandler plus agent enrollment support,
+// used by listeners that serve POST /api/v1/agents/enroll.
+func NewRestHandlerWithAgents(repo ports.IOCRepository, notif notifier.Notifier, llmTriager *llm.LLMTriager, agentRepo ports.AgentRepository, bootstrapToken string) *RestHandler {
+	h := NewRestHandler(repo, notif, llmTriager)
+	h.agentRepo = agentRepo
+	h.bootstrapToken = bootstrapToken
+	return h
+}
+
 // Health check endpoint
 func (h *RestHandler) Health(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
@@ -75,6 +114,8 @@ func (h *RestHandler) CheckIOC(w http.ResponseWriter, r *http.Request) {
 		"version":       ioc.Version,
 		"first_seen":    ioc.FirstSeen.Format(time.RFC3339),
 		"date_ingested": ioc.DateIngested.Format(time.RFC3339),
+		"confidence":    ioc.Confidence,
+		"last_seen":     ioc.LastSeen.Format(time.RFC3339),
 	}
 	writeJSON(w, http.StatusOK, response)
 }
@@ -132,6 +173,12 @@ func (h *RestHandler) SearchIOC(w http.ResponseWriter, r *http.Request) {
 		"count":     len(iocList),
 		"sightings": sightings,
 	}
+	if len(iocList) > 0 {
+		// Confidence/LastSeen are recomputed across every sighting of this
+		// value on each SaveBatch, so any row carries the same up-to-date pair.
+		response["confidence"] = iocList[0].Confidence
+		response["last_seen"] = iocList[0].LastSeen.Format(time.RFC3339)
+	}
 	writeJSON(w, http.StatusOK, response)
 }
 
@@ -151,54 +198,168 @@ func (h *RestHandler) GetIOCFeed(w http.ResponseWriter, r *http.Request) {
 		sinceTime = time.Now().Add(-duration)
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
-	defer cancel()
-
-	switch format {
-	case "cef":
-		data, err := h.cefExporter.Export(ctx, sinceTime)
-		if err != nil {
-			writeError(w, http.StatusInternalServerError, "failed to export CEF feed")
+	limit := 0 // no cap by default; callers doing a full nightly pull want everything
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "invalid 'limit' parameter (must be a non-negative integer)")
 			return
 		}
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte(data)); err != nil {
-			log.Printf("Error writing CEF feed response: %v", err)
-		}
+		limit = parsed
+	}
 
-	case "stix":
-		data, err := h.stixExporter.Export(ctx, sinceTime)
-		if err != nil {
-			writeError(w, http.StatusInternalServerError, "failed to export STIX feed")
+	// Unbounded feeds are streamed row-by-row, so give them room to run
+	// rather than the short timeout used by the bounded lookups above.
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	// "json" is an alias for the streaming NDJSON exporter, and is also the
+	// default when no format is given.
+	if format == "" || format == "json" {
+		format = "ndjson"
+	}
+
+	exp, ok := h.exporters.Get(format)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unsupported format (use 'cef', 'stix', 'misp', 'openioc', 'csv', 'ndjson', or 'json')")
+		return
+	}
+
+	latest, err := h.repo.LatestIngested(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to determine feed freshness")
+		return
+	}
+	if !latest.IsZero() {
+		etag := fmt.Sprintf(`"%s-%d"`, format, latest.UnixNano())
+		w.Header().Set("Last-Modified", latest.UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", etag)
+		if notModified(r, etag, latest) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
-		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte(data)); err != nil {
-			log.Printf("Error writing STIX feed response: %v", err)
+	}
+
+	// No Content-Length is set, so net/http serves this chunked: a SIEM
+	// pulling a nightly full feed reads it as rows arrive instead of
+	// watchtower buffering gigabytes before the first byte goes out.
+	w.Header().Set("Content-Type", exp.ContentType())
+
+	var dest io.Writer = w
+	if acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		dest = gz
+	}
+
+	w.WriteHeader(http.StatusOK)
+	flusher, canFlush := w.(http.Flusher)
+
+	if err := exp.ExportTo(ctx, sinceTime, limit, flushWriter{w: dest, flusher: flusher, canFlush: canFlush}); err != nil {
+		handlerLogger.Error("error streaming feed response", zap.String("format", format), zap.Error(err))
+	}
+}
+
+// acceptsGzip reports whether r's Accept-Encoding header admits gzip.
+func acceptsGzip(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+}
+
+// notModified reports whether r's conditional-GET headers indicate the
+// client's cached copy (identified by etag/lastModified) is still current.
+// If-None-Match takes precedence over If-Modified-Since when both are sent,
+// matching RFC 7232.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return inm == etag
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		if t, err := time.Parse(http.TimeFormat, ims); err == nil {
+			return !lastModified.After(t)
 		}
+	}
+	return false
+}
 
-	case "json", "":
-		// Default: raw JSON format
-		// TODO: Implement JSON feed export
-		writeError(w, http.StatusNotImplemented, "JSON format not yet implemented")
+// flushWriter flushes the underlying http.ResponseWriter after every write
+// so rows reach the client as they're produced instead of sitting in
+// net/http's chunking buffer until the handler returns. When w is itself a
+// *gzip.Writer, gzip buffers internally until its own Flush, so that runs
+// first - otherwise every row would sit in the deflate buffer until the
+// whole export finished, defeating the point of streaming for gzip clients.
+type flushWriter struct {
+	w        io.Writer
+	flusher  http.Flusher
+	canFlush bool
+}
 
-	default:
-		writeError(w, http.StatusBadRequest, "unsupported format (use 'cef', 'stix', or 'json')")
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err == nil {
+		if gz, ok := f.w.(*gzip.Writer); ok {
+			err = gz.Flush()
+		}
+	}
+	if err == nil && f.canFlush {
+		f.flusher.Flush()
 	}
+	return n, err
 }
 
 // SentinelOneWebhook - Receive alerts from SentinelOne
 func (h *RestHandler) SentinelOneWebhook(w http.ResponseWriter, r *http.Request) {
-	var payload SentinelOneAlert
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		log.Printf("‚ùå Failed to decode SentinelOne webhook: %v", err)
+	h.receiveWebhook(w, r, h.sentinelOneAdapter)
+}
+
+// CrowdStrikeWebhook - Receive detections from CrowdStrike Falcon
+func (h *RestHandler) CrowdStrikeWebhook(w http.ResponseWriter, r *http.Request) {
+	h.receiveWebhook(w, r, h.crowdStrikeAdapter)
+}
+
+// GenericWebhook - Receive alerts in the Falco/OSQuery JSON output schema
+func (h *RestHandler) GenericWebhook(w http.ResponseWriter, r *http.Request) {
+	h.receiveWebhook(w, r, h.genericAdapter)
+}
+
+// receiveWebhook authenticates and parses an inbound alert via adapter, then
+// runs it through the enrichment/LLM/notification pipeline every webhook
+// source shares, so adding a new source never means duplicating that
+// pipeline.
+func (h *RestHandler) receiveWebhook(w http.ResponseWriter, r *http.Request, adapter webhook.Adapter) {
+	start := time.Now()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		RecordWebhookProcessing(adapter.Name(), "bad_request", time.Since(start))
+		return
+	}
+
+	if err := adapter.Verify(r, body); err != nil {
+		handlerLogger.Warn("webhook failed verification", zap.String("provider", adapter.Name()), zap.Error(err))
+		writeError(w, http.StatusUnauthorized, "signature verification failed")
+		RecordWebhookProcessing(adapter.Name(), "unauthorized", time.Since(start))
+		return
+	}
+
+	alert, err := adapter.Parse(body)
+	if err != nil {
+		handlerLogger.Warn("failed to decode webhook payload", zap.String("provider", adapter.Name()), zap.Error(err))
 		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		RecordWebhookProcessing(adapter.Name(), "bad_request", time.Since(start))
 		return
 	}
 
-	log.Printf("üì• Received SentinelOne alert: %s (endpoint: %s)", payload.AlertID, payload.Endpoint.ComputerName)
+	h.processAlert(w, r, alert)
+	RecordWebhookProcessing(adapter.Name(), "ok", time.Since(start))
+}
+
+// processAlert enriches alert's indicators against the IOC database, runs
+// LLM triaging (if enabled), and sends a notification - the shared tail end
+// of every webhook source regardless of which Adapter produced alert.
+func (h *RestHandler) processAlert(w http.ResponseWriter, r *http.Request, alert webhook.CanonicalAlert) {
+	handlerLogger.Info("received alert", zap.String("provider", alert.Source), zap.String("alert_id", alert.AlertID), zap.String("endpoint", alert.Endpoint))
 
 	// Enrich each indicator with Watchtower intelligence
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
@@ -206,21 +367,21 @@ func (h *RestHandler) SentinelOneWebhook(w http.ResponseWriter, r *http.Request)
 
 	enrichedIndicators := []EnrichedIndicator{}
 
-	for _, indicator := range payload.Indicators {
+	for _, indicator := range alert.Indicators {
 		// Try exact match first
 		iocs, err := h.repo.FindAllByValue(ctx, indicator.Value)
 		if err != nil {
-			log.Printf("‚ö†Ô∏è  Failed to query IOC %s: %v", indicator.Value, err)
+			handlerLogger.Warn("failed to query IOC", zap.String("ioc_value", indicator.Value), zap.Error(err))
 			continue
 		}
 
 		// If exact match fails, try "contains" search
 		// This handles cases like searching for "198.0.2.12" when DB has "http://198.0.2.12/malware.sh"
 		if len(iocs) == 0 {
-			log.Printf("üîç Exact match failed for %s, trying pattern search...", indicator.Value)
+			handlerLogger.Debug("exact match failed, trying pattern search", zap.String("ioc_value", indicator.Value))
 			iocs, err = h.repo.FindContaining(ctx, indicator.Value)
 			if err != nil {
-				log.Printf("‚ö†Ô∏è  Failed pattern search for %s: %v", indicator.Value, err)
+				handlerLogger.Warn("pattern search failed", zap.String("ioc_value", indicator.Value), zap.Error(err))
 			}
 		}
 
@@ -258,15 +419,15 @@ func (h *RestHandler) SentinelOneWebhook(w http.ResponseWriter, r *http.Request)
 	// Use LLM for intelligent triaging (if enabled)
 	var triageResult *llm.TriageResult
 	if h.llmTriager != nil && h.llmTriager.IsEnabled() {
-		log.Printf("ü§ñ Running LLM triaging for alert %s...", payload.AlertID)
+		handlerLogger.Info("running LLM triage", zap.String("alert_id", alert.AlertID))
 
 		// Build threat context
 		threatContext := llm.ThreatContext{
-			AlertID:        payload.AlertID,
-			ThreatName:     payload.ThreatName,
-			Classification: payload.Classification,
-			Endpoint:       payload.Endpoint.ComputerName,
-			OSType:         payload.Endpoint.OSType,
+			AlertID:        alert.AlertID,
+			ThreatName:     alert.ThreatName,
+			Classification: alert.Classification,
+			Endpoint:       alert.Endpoint,
+			OSType:         alert.OSType,
 			IOCs:           make([]llm.IOCContext, len(enrichedIndicators)),
 		}
 
@@ -285,21 +446,21 @@ func (h *RestHandler) SentinelOneWebhook(w http.ResponseWriter, r *http.Request)
 		// Run triaging
 		result, err := h.llmTriager.Triage(ctx, threatContext)
 		if err != nil {
-			log.Printf("‚ö†Ô∏è  LLM triaging failed: %v", err)
+			handlerLogger.Warn("LLM triaging failed", zap.String("alert_id", alert.AlertID), zap.Error(err))
 		} else {
 			triageResult = result
-			log.Printf("‚úÖ LLM triaging complete - Severity: %s, Priority: %d, Confidence: %d%%",
-				triageResult.Severity, triageResult.Priority, triageResult.Confidence)
+			handlerLogger.Info("LLM triaging complete",
+				zap.String("alert_id", alert.AlertID), zap.String("severity", triageResult.Severity), zap.Int("priority", triageResult.Priority), zap.Int("confidence", triageResult.Confidence))
 
 			// Skip notification for likely false positives (configurable threshold)
 			if triageResult.FalsePositive && triageResult.Confidence >= 80 {
-				log.Printf("‚è≠Ô∏è  Skipping notification - LLM identified as likely false positive")
+				handlerLogger.Info("skipping notification, LLM identified as likely false positive", zap.String("alert_id", alert.AlertID))
 				response := map[string]interface{}{
 					"status":              "received",
-					"alert_id":            payload.AlertID,
+					"alert_id":            alert.AlertID,
 					"indicators_enriched": len(enrichedIndicators),
 					"indicators_in_db":    countEnriched(enrichedIndicators),
-					"slack_notification":  false,
+					"notification_sent":   false,
 					"llm_triaged":         true,
 					"false_positive":      true,
 				}
@@ -309,19 +470,19 @@ func (h *RestHandler) SentinelOneWebhook(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	// Send Slack notification if configured
-	if h.slackNotifier != nil {
+	// Send notification if a channel is configured
+	if h.notifier != nil {
 		// Convert to notifier types
 		notifierAlert := notifier.SentinelOneAlert{
-			AlertID:        payload.AlertID,
-			ThreatName:     payload.ThreatName,
-			Classification: payload.Classification,
+			AlertID:        alert.AlertID,
+			ThreatName:     alert.ThreatName,
+			Classification: alert.Classification,
 			Endpoint: struct {
 				ComputerName string
 				OSType       string
 			}{
-				ComputerName: payload.Endpoint.ComputerName,
-				OSType:       payload.Endpoint.OSType,
+				ComputerName: alert.Endpoint,
+				OSType:       alert.OSType,
 			},
 		}
 
@@ -351,25 +512,25 @@ func (h *RestHandler) SentinelOneWebhook(w http.ResponseWriter, r *http.Request)
 				FalsePositive: triageResult.FalsePositive,
 				Confidence:    triageResult.Confidence,
 			}
-			err = h.slackNotifier.NotifySentinelOneDetectionWithTriage(notifierAlert, notifierEnriched, notifierTriage)
+			err = h.notifier.NotifySentinelOneDetectionWithTriage(notifierAlert, notifierEnriched, notifierTriage)
 		} else {
-			err = h.slackNotifier.NotifySentinelOneDetection(notifierAlert, notifierEnriched)
+			err = h.notifier.NotifySentinelOneDetection(notifierAlert, notifierEnriched)
 		}
 
 		if err != nil {
-			log.Printf("‚ö†Ô∏è  Failed to send Slack notification: %v", err)
+			handlerLogger.Warn("failed to send notification", zap.String("alert_id", alert.AlertID), zap.Error(err))
 		} else {
-			log.Printf("‚úÖ Slack notification sent for alert %s", payload.AlertID)
+			handlerLogger.Info("notification sent", zap.String("alert_id", alert.AlertID))
 		}
 	}
 
 	// Respond to SentinelOne
 	response := map[string]interface{}{
 		"status":              "received",
-		"alert_id":            payload.AlertID,
+		"alert_id":            alert.AlertID,
 		"indicators_enriched": len(enrichedIndicators),
 		"indicators_in_db":    countEnriched(enrichedIndicators),
-		"slack_notification":  h.slackNotifier != nil,
+		"notification_sent":   h.notifier != nil,
 		"llm_triaged":         triageResult != nil,
 	}
 	writeJSON(w, http.StatusOK, response)
@@ -381,7 +542,7 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Error encoding JSON response: %v", err)
+		handlerLogger.Error("failed to encode JSON response", zap.Error(err))
 	}
 }
 
@@ -411,28 +572,6 @@ func countEnriched(indicators []EnrichedIndicator) int {
 	return count
 }
 
-// SentinelOne webhook payload structures
-
-type SentinelOneAlert struct {
-	AlertID        string              `json:"alertId"`
-	ThreatName     string              `json:"threatName"`
-	Classification string              `json:"classification"`
-	Indicators     []SentinelOneIOC    `json:"indicators"`
-	Endpoint       SentinelOneEndpoint `json:"endpoint"`
-	Timestamp      string              `json:"timestamp"`
-}
-
-type SentinelOneIOC struct {
-	Type  string `json:"type"` // SHA256, IPV4, IPV6, DNS, URL
-	Value string `json:"value"`
-}
-
-type SentinelOneEndpoint struct {
-	ComputerName string `json:"computerName"`
-	OSType       string `json:"osType"`
-	AgentVersion string `json:"agentVersion"`
-}
-
 type EnrichedIndicator struct {
 	Type        string    `json:"type"`
 	Value       string    `json:"value"`
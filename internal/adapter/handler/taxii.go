@@ -0,0 +1,399 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hive-corporation/watchtower/internal/adapter/exporter"
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+)
+
+const (
+	taxiiDefaultPageSize = 100
+	taxiiMaxPageSize     = 10000
+	// taxiiCollectionID is the single collection Watchtower exposes: every
+	// ingested IOC, as STIX 2.1 indicators.
+	taxiiCollectionID = "indicators"
+	// taxiiContentType is the TAXII 2.1 media type every collection-access
+	// response is served (and negotiated via Accept) as.
+	taxiiContentType = "application/taxii+json;version=2.1"
+)
+
+// TAXIIAuthConfig configures the bearer token or basic-auth credential
+// TAXIIHandler requires for collection access (Collections, Collection,
+// Objects, Manifest). Discovery and the API root stay open, matching most
+// TIPs' expectation that they can probe a server's root before configuring
+// credentials. A zero-value TAXIIAuthConfig (or a nil *TAXIIAuthConfig)
+// leaves collection access open too.
+type TAXIIAuthConfig struct {
+	BearerToken string
+	BasicUser   string
+	BasicPass   string
+}
+
+// TAXIIHandler serves a minimal TAXII 2.1 API root backed by the same
+// ports.IOCRepository the REST/STIX export path uses, so downstream TIPs
+// (OpenCTI, MISP, Anomali) can pull deltas by polling objects/ instead of
+// re-downloading the full STIX bundle on every sync.
+type TAXIIHandler struct {
+	stix *exporter.STIXExporter
+	auth *TAXIIAuthConfig
+}
+
+// NewTAXIIHandler returns a TAXIIHandler backed by repo. auth may be nil to
+// leave collection access unauthenticated.
+func NewTAXIIHandler(repo ports.IOCRepository, auth *TAXIIAuthConfig) *TAXIIHandler {
+	return &TAXIIHandler{stix: exporter.NewSTIXExporter(repo), auth: auth}
+}
+
+// Discovery serves GET /taxii2/
+func (h *TAXIIHandler) Discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"title":       "Watchtower TAXII 2.1 Server",
+		"description": "Threat intelligence feed served as STIX 2.1 indicators",
+		"default":     "/taxii2/",
+		"api_roots":   []string{"/taxii2/"},
+	})
+}
+
+// APIRoot serves GET /taxii2/api-root-info — TAXII 2.1 conflates discovery
+// and the sole API root for a single-root deployment like this one.
+func (h *TAXIIHandler) APIRoot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"title":              "Watchtower",
+		"versions":           []string{taxiiContentType},
+		"max_content_length": taxiiMaxPageSize,
+	})
+}
+
+// Collections serves GET /taxii2/collections/
+func (h *TAXIIHandler) Collections(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"collections": []taxiiCollection{h.collection()},
+	})
+}
+
+// Collection serves GET /taxii2/collections/{id}/
+func (h *TAXIIHandler) Collection(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, r) {
+		return
+	}
+	if id := mux.Vars(r)["id"]; id != taxiiCollectionID {
+		writeError(w, http.StatusNotFound, "unknown collection")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.collection())
+}
+
+func (h *TAXIIHandler) collection() taxiiCollection {
+	return taxiiCollection{
+		ID:          taxiiCollectionID,
+		Title:       "Watchtower Indicators",
+		Description: "All IOCs ingested by Watchtower, as STIX 2.1 indicators",
+		CanRead:     true,
+		CanWrite:    false,
+		MediaTypes:  []string{"application/stix+json;version=2.1"},
+	}
+}
+
+type taxiiCollection struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	CanRead     bool     `json:"can_read"`
+	CanWrite    bool     `json:"can_write"`
+	MediaTypes  []string `json:"media_types"`
+}
+
+// Objects serves GET /taxii2/collections/{id}/objects/, returning a page of
+// STIX indicators as a TAXII envelope. Clients page with the standard HTTP
+// Range header ("items 0-99") and may pass added_after for incremental
+// polling; the response echoes Content-Range, X-TAXII-Date-Added-First/-Last,
+// and includes "more"/"next" so clients can continue without re-fetching
+// objects they already have.
+func (h *TAXIIHandler) Objects(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, r) {
+		return
+	}
+	if id := mux.Vars(r)["id"]; id != taxiiCollectionID {
+		writeError(w, http.StatusNotFound, "unknown collection")
+		return
+	}
+
+	since, err := parseAddedAfter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	items, err := h.stix.FetchEnvelopeItems(ctx, since, taxiiMaxPageSize)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch objects")
+		return
+	}
+
+	start, end := parseRange(r.Header.Get("Range"), parseLimit(r), len(items))
+	page := items[start:end]
+	more := end < len(items)
+
+	objects := make([]exporter.STIXObject, len(page))
+	for i, item := range page {
+		objects[i] = item.Object
+	}
+
+	w.Header().Set("Content-Type", taxiiContentType)
+	writeDateAddedHeaders(w, page)
+	if more || start > 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/%d", start, max(end-1, start), len(items)))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	envelope := map[string]interface{}{
+		"objects": objects,
+		"more":    more,
+	}
+	if more {
+		envelope["next"] = strconv.Itoa(end)
+	}
+
+	if err := json.NewEncoder(w).Encode(envelope); err != nil {
+		log.Printf("Error writing TAXII objects response: %v", err)
+	}
+}
+
+// Manifest serves GET /taxii2/collections/{id}/manifest/, returning the
+// TAXII manifest resource for the same page Objects would return: just
+// id/date_added/version per object, so a client can check what changed
+// before paying for the full objects/ pull. Paging and added_after behave
+// identically to Objects.
+func (h *TAXIIHandler) Manifest(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, r) {
+		return
+	}
+	if id := mux.Vars(r)["id"]; id != taxiiCollectionID {
+		writeError(w, http.StatusNotFound, "unknown collection")
+		return
+	}
+
+	since, err := parseAddedAfter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	items, err := h.stix.FetchEnvelopeItems(ctx, since, taxiiMaxPageSize)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to fetch objects")
+		return
+	}
+
+	start, end := parseRange(r.Header.Get("Range"), parseLimit(r), len(items))
+	page := items[start:end]
+	more := end < len(items)
+
+	entries := make([]taxiiManifestEntry, len(page))
+	for i, item := range page {
+		entries[i] = taxiiManifestEntry{
+			ID:        item.Object.ID,
+			DateAdded: item.DateAdded.UTC().Format(time.RFC3339),
+			Version:   item.Object.Modified,
+		}
+	}
+
+	w.Header().Set("Content-Type", taxiiContentType)
+	writeDateAddedHeaders(w, page)
+	if more || start > 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/%d", start, max(end-1, start), len(items)))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	envelope := map[string]interface{}{
+		"objects": entries,
+		"more":    more,
+	}
+	if more {
+		envelope["next"] = strconv.Itoa(end)
+	}
+
+	if err := json.NewEncoder(w).Encode(envelope); err != nil {
+		log.Printf("Error writing TAXII manifest response: %v", err)
+	}
+}
+
+type taxiiManifestEntry struct {
+	ID        string `json:"id"`
+	DateAdded string `json:"date_added"`
+	Version   string `json:"version"`
+}
+
+// checkAccess enforces auth (if configured) and TAXII 2.1 content
+// negotiation for a collection-access endpoint, writing the appropriate
+// error response and returning false if either check fails.
+func (h *TAXIIHandler) checkAccess(w http.ResponseWriter, r *http.Request) bool {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="taxii2", Basic realm="taxii2"`)
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return false
+	}
+	if !acceptsTAXII21(r) {
+		writeError(w, http.StatusNotAcceptable, "unsupported media type, expected "+taxiiContentType)
+		return false
+	}
+	return true
+}
+
+// authorized reports whether r carries the bearer token or basic-auth
+// credential configured on h.auth. A nil auth (or one with neither set)
+// leaves collection access open.
+func (h *TAXIIHandler) authorized(r *http.Request) bool {
+	if h.auth == nil {
+		return true
+	}
+
+	if h.auth.BearerToken != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.auth.BearerToken)) == 1
+	}
+
+	if h.auth.BasicUser != "" || h.auth.BasicPass != "" {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(user), []byte(h.auth.BasicUser)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(pass), []byte(h.auth.BasicPass)) == 1
+	}
+
+	return true
+}
+
+// acceptsTAXII21 reports whether r's Accept header (if any) admits the
+// TAXII 2.1 media type. An empty or wildcard Accept is treated as
+// accepting it; an explicit version= parameter other than 2.1 is rejected.
+func acceptsTAXII21(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" || strings.Contains(accept, "*/*") {
+		return true
+	}
+	if !strings.Contains(accept, "application/taxii+json") {
+		return false
+	}
+	return !strings.Contains(accept, "version=") || strings.Contains(accept, "version=2.1")
+}
+
+// parseAddedAfter parses the added_after query parameter Objects/Manifest
+// both filter on, returning the zero time (no filter) when it's absent.
+func parseAddedAfter(r *http.Request) (time.Time, error) {
+	addedAfter := r.URL.Query().Get("added_after")
+	if addedAfter == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, addedAfter)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid 'added_after' (use RFC3339)")
+	}
+	return parsed, nil
+}
+
+// writeDateAddedHeaders sets X-TAXII-Date-Added-First/-Last from the
+// earliest and latest DateAdded in page, the TAXII 2.1 convention for
+// clients that want the added-date range of a page without inspecting
+// every object. A nil/empty page sets neither header.
+func writeDateAddedHeaders(w http.ResponseWriter, page []exporter.TAXIIEnvelopeItem) {
+	if len(page) == 0 {
+		return
+	}
+
+	first, last := page[0].DateAdded, page[0].DateAdded
+	for _, item := range page[1:] {
+		if item.DateAdded.Before(first) {
+			first = item.DateAdded
+		}
+		if item.DateAdded.After(last) {
+			last = item.DateAdded
+		}
+	}
+
+	w.Header().Set("X-TAXII-Date-Added-First", first.UTC().Format(time.RFC3339))
+	w.Header().Set("X-TAXII-Date-Added-Last", last.UTC().Format(time.RFC3339))
+}
+
+// parseLimit parses the non-standard "limit" query parameter some clients
+// (and the REST /feed endpoint) use instead of a Range header, returning 0
+// (no override) when absent or invalid.
+func parseLimit(r *http.Request) int {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return 0
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0
+	}
+	return limit
+}
+
+// parseRange parses an "items N-M" Range header, the TAXII 2.1 paging
+// convention, into a half-open [start,end) slice bound, defaulting to the
+// first page of taxiiDefaultPageSize (or limit, if given and smaller than
+// taxiiMaxPageSize) when no Range header is present.
+func parseRange(header string, limit, total int) (start, end int) {
+	pageSize := taxiiDefaultPageSize
+	if limit > 0 && limit < taxiiMaxPageSize {
+		pageSize = limit
+	}
+	start, end = 0, pageSize
+	if header != "" {
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(header, "items="), "items ")
+		if parts := strings.SplitN(trimmed, "-", 2); len(parts) == 2 {
+			if s, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
+				start = s
+			}
+			if e, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+				end = e + 1
+			}
+		}
+	}
+
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// CTIReputation is the verdict a CTIProvider returns for a single IOC.
+type CTIReputation string
+
+const (
+	CTIReputationUnknown    CTIReputation = "unknown"
+	CTIReputationBenign     CTIReputation = "benign"
+	CTIReputationSuspicious CTIReputation = "suspicious"
+	CTIReputationMalicious  CTIReputation = "malicious"
+)
+
+// CTIVerdict is what a CTIProvider reports about one IOC: real-time
+// reputation plus enough context (categories, first seen, corroborating
+// source count) for the guardrails to weigh it the way they already weigh
+// our own InDatabase/ThreatTypes fields.
+type CTIVerdict struct {
+	Reputation       CTIReputation
+	ThreatCategories []string
+	FirstSeen        time.Time
+	Tags             []string
+	SourceCount      int
+
+	// AttackDetails lists specific attack scenarios the source observed this
+	// IOC participating in (e.g. CrowdSec's "attack_details"), letting
+	// guardrails tell a confirmed-malicious IOC with concrete evidence apart
+	// from one that's merely tagged malicious with no further detail.
+	AttackDetails []string
+
+	// BackgroundNoiseScore is a 0-100 "how common is this activity across
+	// the whole community" score some sources report; high values suggest
+	// mass-scanning noise rather than a targeted hit.
+	BackgroundNoiseScore int
+
+	// KnownService reports whether the source recognizes this IOC as a
+	// known-benign crawler, scanner, or VPN/proxy exit node, which guardrails
+	// treat like a known-good indicator even without an explicit benign
+	// reputation.
+	KnownService bool
+}
+
+// CTIProvider looks up real-time reputation for a single IOC from an
+// external cyber threat intelligence source, replacing (or corroborating)
+// the static KnownGoodIndicators/HighRiskThreatTypes lists the guardrails
+// used to rely on exclusively.
+type CTIProvider interface {
+	LookupIOC(ctx context.Context, iocType, value string) (CTIVerdict, error)
+}
+
+// lookupCTIVerdict consults provider for ioc, degrading to
+// CTIReputationUnknown (so callers fall through to the existing static
+// logic) when no provider is configured or the lookup itself fails.
+func lookupCTIVerdict(ctx context.Context, provider CTIProvider, ioc IOCContext) CTIVerdict {
+	if provider == nil {
+		return CTIVerdict{Reputation: CTIReputationUnknown}
+	}
+
+	verdict, err := provider.LookupIOC(ctx, ioc.Type, ioc.Value)
+	if err != nil {
+		log.Printf("⚠️  CTI lookup failed for %s %s: %v", ioc.Type, ioc.Value, err)
+		RecordCTIError()
+		return CTIVerdict{Reputation: CTIReputationUnknown}
+	}
+	return verdict
+}
+
+// CTIProviderFromEnv builds the CTIProvider guardrails should consult, from
+// CTI_PROVIDER ("crowdsec", "virustotal", "otx"), CTI_API_KEY, and the
+// CTI_CACHE_TTL/CTI_CACHE_SIZE cache parameters. Returns nil when
+// CTI_PROVIDER is unset, so guardrails fall back to their static lists
+// exactly as before.
+func CTIProviderFromEnv(client *http.Client) CTIProvider {
+	var provider CTIProvider
+
+	switch os.Getenv("CTI_PROVIDER") {
+	case "crowdsec":
+		provider = NewCrowdSecCTIProvider(client, os.Getenv("CTI_API_KEY"))
+	case "virustotal":
+		provider = NewVirusTotalCTIProvider(client, os.Getenv("CTI_API_KEY"))
+	case "otx":
+		provider = NewOTXCTIProvider(client, os.Getenv("CTI_API_KEY"))
+	default:
+		return nil
+	}
+
+	cacheConfig := DefaultCTICacheConfig()
+	if size := os.Getenv("CTI_CACHE_SIZE"); size != "" {
+		if n, err := strconv.Atoi(size); err == nil && n > 0 {
+			cacheConfig.Size = n
+		}
+	}
+	if ttl := os.Getenv("CTI_CACHE_TTL"); ttl != "" {
+		if d, err := time.ParseDuration(ttl); err == nil && d > 0 {
+			cacheConfig.HitTTL = d
+		}
+	}
+
+	return NewCachingCTIProvider(provider, cacheConfig)
+}
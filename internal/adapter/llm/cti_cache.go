@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// CTICacheConfig controls CachingCTIProvider's bounded LRU + TTL cache.
+// Misses get their own (shorter) TTL since an upstream that briefly has no
+// data on an IOC is cheaper to re-check than invalidating a known-bad
+// verdict too early.
+type CTICacheConfig struct {
+	Size    int           // Max cached entries before the LRU evicts (default: 10000)
+	HitTTL  time.Duration // How long a successful lookup stays cached (default: 1h)
+	MissTTL time.Duration // How long an "unknown" verdict stays cached (default: 5m)
+}
+
+// DefaultCTICacheConfig returns the default cache configuration.
+func DefaultCTICacheConfig() CTICacheConfig {
+	return CTICacheConfig{
+		Size:    10000,
+		HitTTL:  1 * time.Hour,
+		MissTTL: 5 * time.Minute,
+	}
+}
+
+type ctiCacheEntry struct {
+	key       string
+	verdict   CTIVerdict
+	expiresAt time.Time
+}
+
+// CachingCTIProvider wraps a CTIProvider with a bounded LRU + TTL cache so
+// repeated lookups for the same IOC (common across alerts on the same
+// endpoint) don't hammer the upstream API.
+type CachingCTIProvider struct {
+	inner  CTIProvider
+	config CTICacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // key -> node in order
+	order   *list.List               // front = most recently used
+}
+
+// NewCachingCTIProvider wraps inner with an LRU+TTL cache. Returns inner
+// unwrapped if inner is nil, so callers can compose this unconditionally.
+func NewCachingCTIProvider(inner CTIProvider, config CTICacheConfig) CTIProvider {
+	if inner == nil {
+		return nil
+	}
+	if config.Size <= 0 {
+		config.Size = DefaultCTICacheConfig().Size
+	}
+	if config.HitTTL <= 0 {
+		config.HitTTL = DefaultCTICacheConfig().HitTTL
+	}
+	if config.MissTTL <= 0 {
+		config.MissTTL = DefaultCTICacheConfig().MissTTL
+	}
+	return &CachingCTIProvider{
+		inner:   inner,
+		config:  config,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *CachingCTIProvider) LookupIOC(ctx context.Context, iocType, value string) (CTIVerdict, error) {
+	key := iocType + ":" + value
+
+	if verdict, ok := c.get(key); ok {
+		RecordCTIHit()
+		return verdict, nil
+	}
+
+	verdict, err := c.inner.LookupIOC(ctx, iocType, value)
+	if err != nil {
+		RecordCTIError()
+		return verdict, err
+	}
+
+	RecordCTIMiss()
+	ttl := c.config.MissTTL
+	if verdict.Reputation != CTIReputationUnknown {
+		ttl = c.config.HitTTL
+	}
+	c.put(key, verdict, ttl)
+
+	return verdict, nil
+}
+
+func (c *CachingCTIProvider) get(key string) (CTIVerdict, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return CTIVerdict{}, false
+	}
+
+	entry := elem.Value.(*ctiCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return CTIVerdict{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.verdict, true
+}
+
+func (c *CachingCTIProvider) put(key string, verdict CTIVerdict, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*ctiCacheEntry).verdict = verdict
+		elem.Value.(*ctiCacheEntry).expiresAt = time.Now().Add(ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	entry := &ctiCacheEntry{key: key, verdict: verdict, expiresAt: time.Now().Add(ttl)}
+	elem := c.order.PushFront(entry)
+	c.entries[key] = elem
+
+	for c.order.Len() > c.config.Size {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*ctiCacheEntry).key)
+	}
+}
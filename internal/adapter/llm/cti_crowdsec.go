@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// crowdSecCTIURL is CrowdSec's "smoke" lookup - it only covers IP addresses,
+// unlike the richer provider/consumer intel CrowdSec also sells.
+const crowdSecCTIURL = "https://cti.api.crowdsec.net/v2/smoke/%s"
+
+// CrowdSecCTIProvider looks up IP reputation against the CrowdSec CTI API.
+type CrowdSecCTIProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+func NewCrowdSecCTIProvider(client *http.Client, apiKey string) *CrowdSecCTIProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &CrowdSecCTIProvider{client: client, apiKey: apiKey}
+}
+
+type crowdSecSmokeResponse struct {
+	IPRangeScore         int    `json:"ip_range_score"`
+	Reputation           string `json:"reputation"` // "known", "benign", "malicious"
+	BackgroundNoiseScore int    `json:"background_noise_score"`
+	Behaviors            []struct {
+		Label    string `json:"label"`
+		Category string `json:"category"`
+	} `json:"behaviors"`
+	Classifications struct {
+		Classifications []struct {
+			Name string `json:"name"` // e.g. "known_crawler", "tor_exit_node"
+		} `json:"classifications"`
+	} `json:"classifications"`
+	AttackDetails []struct {
+		Name string `json:"name"`
+	} `json:"attack_details"`
+	History struct {
+		FirstSeen string `json:"first_seen"`
+	} `json:"history"`
+	References []string `json:"references"`
+}
+
+// crowdSecKnownServiceClassifications are classifications CrowdSec reports
+// for well-known, generally non-malicious automated traffic.
+var crowdSecKnownServiceClassifications = map[string]bool{
+	"known_crawler": true,
+	"known_scanner": true,
+	"tor_exit_node": true,
+	"vpn":           true,
+}
+
+// LookupIOC only has reputation data for IP addresses; every other IOC type
+// degrades to CTIReputationUnknown so guardrails fall through to their
+// static lists, per the provider's documented IP-only scope.
+func (p *CrowdSecCTIProvider) LookupIOC(ctx context.Context, iocType, value string) (CTIVerdict, error) {
+	if !isIPIndicator(iocType) {
+		return CTIVerdict{Reputation: CTIReputationUnknown}, nil
+	}
+	if p.apiKey == "" {
+		return CTIVerdict{}, fmt.Errorf("CrowdSec CTI API key is missing")
+	}
+
+	url := fmt.Sprintf(crowdSecCTIURL, value)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return CTIVerdict{}, err
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return CTIVerdict{}, err
+	}
+	defer resp.Body.Close()
+
+	// CrowdSec returns 404 for IPs it has no data on at all - that's a
+	// legitimate "we don't know", not an error.
+	if resp.StatusCode == http.StatusNotFound {
+		return CTIVerdict{Reputation: CTIReputationUnknown}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CTIVerdict{}, fmt.Errorf("CrowdSec CTI API error: status %d", resp.StatusCode)
+	}
+
+	var data crowdSecSmokeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return CTIVerdict{}, fmt.Errorf("failed to decode CrowdSec CTI response: %w", err)
+	}
+
+	categories := make([]string, 0, len(data.Behaviors))
+	for _, b := range data.Behaviors {
+		categories = append(categories, b.Category)
+	}
+
+	knownService := false
+	for _, c := range data.Classifications.Classifications {
+		categories = append(categories, c.Name)
+		if crowdSecKnownServiceClassifications[c.Name] {
+			knownService = true
+		}
+	}
+
+	attackDetails := make([]string, 0, len(data.AttackDetails))
+	for _, a := range data.AttackDetails {
+		if a.Name != "" {
+			attackDetails = append(attackDetails, a.Name)
+		}
+	}
+
+	firstSeen, _ := time.Parse(time.RFC3339, data.History.FirstSeen)
+
+	return CTIVerdict{
+		Reputation:           mapCrowdSecReputation(data.Reputation),
+		ThreatCategories:     categories,
+		FirstSeen:            firstSeen,
+		SourceCount:          len(data.References),
+		AttackDetails:        attackDetails,
+		BackgroundNoiseScore: data.BackgroundNoiseScore,
+		KnownService:         knownService,
+	}, nil
+}
+
+func mapCrowdSecReputation(reputation string) CTIReputation {
+	switch strings.ToLower(reputation) {
+	case "malicious":
+		return CTIReputationMalicious
+	case "suspicious":
+		return CTIReputationSuspicious
+	case "benign", "known":
+		return CTIReputationBenign
+	default:
+		return CTIReputationUnknown
+	}
+}
+
+// isIPIndicator recognizes the handful of casings the rest of the codebase
+// uses for an IP IOC (domain.IPAddress's "ip", plus the upstream alert
+// payload's "IPV4"/"IPv4"/"IPv6").
+func isIPIndicator(iocType string) bool {
+	switch strings.ToLower(iocType) {
+	case "ip", "ipv4", "ipv6":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+)
+
+// VirusTotalCTIProvider is a placeholder CTIProvider for VirusTotal. It
+// satisfies the interface so CTI_PROVIDER=virustotal can be selected without
+// breaking startup, but doesn't call out yet - every lookup degrades to
+// CTIReputationUnknown until the integration is implemented.
+type VirusTotalCTIProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+func NewVirusTotalCTIProvider(client *http.Client, apiKey string) *VirusTotalCTIProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &VirusTotalCTIProvider{client: client, apiKey: apiKey}
+}
+
+func (p *VirusTotalCTIProvider) LookupIOC(ctx context.Context, iocType, value string) (CTIVerdict, error) {
+	return CTIVerdict{Reputation: CTIReputationUnknown}, nil
+}
+
+// OTXCTIProvider is a placeholder CTIProvider for AlienVault OTX's
+// reputation/indicator-details endpoints (distinct from provider.OTXProvider,
+// which ingests pulses rather than looking up a single IOC on demand).
+type OTXCTIProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+func NewOTXCTIProvider(client *http.Client, apiKey string) *OTXCTIProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &OTXCTIProvider{client: client, apiKey: apiKey}
+}
+
+func (p *OTXCTIProvider) LookupIOC(ctx context.Context, iocType, value string) (CTIVerdict, error) {
+	return CTIVerdict{Reputation: CTIReputationUnknown}, nil
+}
@@ -0,0 +1,296 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// badHostFailureThreshold is the number of consecutive failed deliveries
+	// (any error from ResilientClient.Do, HTTP error statuses included) on a
+	// host's queue before it is auto-paused for a cool-off window.
+	badHostFailureThreshold = 5
+	// badHostCooldown is how long an auto-paused host's queue stays paused
+	// before deliveries resume.
+	badHostCooldown = 30 * time.Second
+	// hostQueueBuffer bounds how many requests can sit in a host's queue
+	// before Enqueue blocks the caller.
+	hostQueueBuffer = 256
+)
+
+// Result is the outcome of a delivered Request, sent back over its result channel.
+type Result struct {
+	Response *http.Response
+	Err      error
+}
+
+// Request wraps an *http.Request with the target/routing metadata the
+// DeliveryPool needs to queue it per-host and report the outcome back to the
+// caller asynchronously.
+type Request struct {
+	HTTPRequest *http.Request
+	Host        string        // queue key; defaults to HTTPRequest.URL.Hostname()
+	Tag         string        // opaque label used by CancelByTag (e.g. provider name)
+	Deadline    time.Time     // zero means no deadline beyond the request's own context
+	RetryAfter  time.Duration // hint from a prior 429/Retry-After, honored before first attempt
+
+	resultCh chan Result
+}
+
+// NewRequest builds a Request ready for DeliveryPool.Enqueue. The request's
+// own context is preserved end-to-end; the worker never substitutes a fresh
+// context.Background().
+func NewRequest(httpReq *http.Request, tag string) *Request {
+	host := httpReq.URL.Hostname()
+	return &Request{
+		HTTPRequest: httpReq,
+		Host:        host,
+		Tag:         tag,
+		resultCh:    make(chan Result, 1),
+	}
+}
+
+// hostQueue is the per-host delivery state: a bounded channel of pending
+// requests, the workers draining it, and the bad-host circuit tracking that
+// lets one broken host stall only its own queue.
+type hostQueue struct {
+	host     string
+	requests chan *Request
+	inFlight int64 // deliveries currently being worked by this queue's workers
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	pausedUntil         time.Time
+}
+
+func (q *hostQueue) isPaused() (bool, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pausedUntil.IsZero() {
+		return false, 0
+	}
+	remaining := time.Until(q.pausedUntil)
+	if remaining <= 0 {
+		q.pausedUntil = time.Time{}
+		return false, 0
+	}
+	return true, remaining
+}
+
+func (q *hostQueue) pause(dur time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pausedUntil = time.Now().Add(dur)
+}
+
+func (q *hostQueue) resume() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pausedUntil = time.Time{}
+	q.consecutiveFailures = 0
+}
+
+func (q *hostQueue) recordOutcome(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err == nil {
+		q.consecutiveFailures = 0
+		return
+	}
+	q.consecutiveFailures++
+	if q.consecutiveFailures >= badHostFailureThreshold {
+		q.pausedUntil = time.Now().Add(badHostCooldown)
+		q.consecutiveFailures = 0
+	}
+}
+
+// DeliveryPool is an asynchronous front-end for ResilientClient: callers
+// enqueue Requests instead of calling Do directly, and a fixed number of
+// workers per destination host drain that host's queue independently, so a
+// slow or broken host only stalls its own deliveries.
+type DeliveryPool struct {
+	client         *ResilientClient
+	workersPerHost int
+
+	mu     sync.Mutex
+	queues map[string]*hostQueue
+	wg     sync.WaitGroup
+}
+
+// NewDeliveryPool creates a pool that delivers through client, spawning
+// workersPerHost goroutines for every distinct host it sees.
+func NewDeliveryPool(client *ResilientClient, workersPerHost int) *DeliveryPool {
+	if workersPerHost <= 0 {
+		workersPerHost = 1
+	}
+	return &DeliveryPool{
+		client:         client,
+		workersPerHost: workersPerHost,
+		queues:         make(map[string]*hostQueue),
+	}
+}
+
+// Stop closes every host queue and waits for their workers to drain and
+// exit. Requests already sitting in a queue are delivered normally before
+// its worker returns; nothing new may be enqueued once Stop has been
+// called. It is safe to call Stop even if no host queue was ever created.
+func (p *DeliveryPool) Stop() {
+	p.mu.Lock()
+	queues := make([]*hostQueue, 0, len(p.queues))
+	for _, q := range p.queues {
+		queues = append(queues, q)
+	}
+	p.mu.Unlock()
+
+	for _, q := range queues {
+		close(q.requests)
+	}
+	p.wg.Wait()
+}
+
+// Enqueue queues req on its host's delivery queue, spawning that host's
+// workers on first use, and returns the channel its Result will arrive on.
+func (p *DeliveryPool) Enqueue(req *Request) <-chan Result {
+	q := p.queueFor(req.Host)
+	q.requests <- req
+	RecordQueueDepth(req.Host, len(q.requests))
+	return req.resultCh
+}
+
+func (p *DeliveryPool) queueFor(host string) *hostQueue {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if q, ok := p.queues[host]; ok {
+		return q
+	}
+
+	q := &hostQueue{
+		host:     host,
+		requests: make(chan *Request, hostQueueBuffer),
+	}
+	p.queues[host] = q
+
+	for i := 0; i < p.workersPerHost; i++ {
+		p.wg.Add(1)
+		go p.worker(q)
+	}
+
+	return q
+}
+
+func (p *DeliveryPool) worker(q *hostQueue) {
+	defer p.wg.Done()
+	for req := range q.requests {
+		RecordQueueDepth(q.host, len(q.requests))
+		p.deliver(q, req)
+	}
+}
+
+func (p *DeliveryPool) deliver(q *hostQueue, req *Request) {
+	ctx := req.HTTPRequest.Context()
+
+	if req.RetryAfter > 0 {
+		select {
+		case <-time.After(req.RetryAfter):
+		case <-ctx.Done():
+			req.resultCh <- Result{Err: ctx.Err()}
+			return
+		}
+	}
+
+	if paused, remaining := q.isPaused(); paused {
+		select {
+		case <-time.After(remaining):
+		case <-ctx.Done():
+			req.resultCh <- Result{Err: ctx.Err()}
+			return
+		}
+	}
+
+	if !req.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, req.Deadline)
+		defer cancel()
+		req.HTTPRequest = req.HTTPRequest.WithContext(ctx)
+	}
+
+	RecordInFlight(q.host, int(atomic.AddInt64(&q.inFlight, 1)))
+	resp, err := p.client.Do(req.HTTPRequest)
+	RecordInFlight(q.host, int(atomic.AddInt64(&q.inFlight, -1)))
+
+	q.recordOutcome(err)
+	req.resultCh <- Result{Response: resp, Err: err}
+}
+
+// PauseHost suspends delivery for a host's queue for dur, without touching
+// requests already in flight. Useful for operators reacting to an incident
+// on a specific upstream before the automatic bad-host circuit would trip.
+func (p *DeliveryPool) PauseHost(host string, dur time.Duration) {
+	p.queueFor(host).pause(dur)
+}
+
+// ResumeHost clears a paused or auto-tripped host's cool-off immediately.
+func (p *DeliveryPool) ResumeHost(host string) {
+	p.queueFor(host).resume()
+}
+
+// DrainForHost flushes every request currently queued (but not yet picked up
+// by a worker) for host, failing each with a drained error. It does not stop
+// the host's workers, which remain ready for future enqueues.
+func (p *DeliveryPool) DrainForHost(host string) {
+	p.mu.Lock()
+	q, ok := p.queues[host]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	for {
+		select {
+		case req := <-q.requests:
+			req.resultCh <- Result{Err: fmt.Errorf("delivery pool: host %s queue drained", host)}
+		default:
+			RecordQueueDepth(host, 0)
+			return
+		}
+	}
+}
+
+// CancelByTag removes every queued request (on any host) whose Tag matches,
+// failing each with a cancellation error. It's meant for dropping in-flight
+// deliveries for a provider that was removed at runtime. Requests already
+// picked up by a worker are not affected.
+func (p *DeliveryPool) CancelByTag(tag string) {
+	p.mu.Lock()
+	queues := make([]*hostQueue, 0, len(p.queues))
+	for _, q := range p.queues {
+		queues = append(queues, q)
+	}
+	p.mu.Unlock()
+
+	for _, q := range queues {
+		var kept []*Request
+		draining := true
+		for draining {
+			select {
+			case req := <-q.requests:
+				if req.Tag == tag {
+					req.resultCh <- Result{Err: fmt.Errorf("delivery pool: request tagged %q canceled", tag)}
+				} else {
+					kept = append(kept, req)
+				}
+			default:
+				draining = false
+			}
+		}
+		for _, req := range kept {
+			q.requests <- req
+		}
+		RecordQueueDepth(q.host, len(q.requests))
+	}
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestDeliveryPool(t *testing.T, handler http.HandlerFunc) (*DeliveryPool, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+
+	config := ResilientClientConfig{
+		EnableCircuitBreaker: false,
+		MaxRetries:           0,
+	}
+	client := NewResilientClient(5*time.Second, config)
+
+	pool := NewDeliveryPool(client, 2)
+	t.Cleanup(pool.Stop)
+	return pool, server
+}
+
+func TestDeliveryPool_EnqueueDeliversRequest(t *testing.T) {
+	pool, server := newTestDeliveryPool(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	httpReq, _ := http.NewRequest("GET", server.URL, nil)
+	resultCh := pool.Enqueue(NewRequest(httpReq, "test-provider"))
+
+	select {
+	case result := <-resultCh:
+		if result.Err != nil {
+			t.Fatalf("unexpected error: %v", result.Err)
+		}
+		if result.Response.StatusCode != http.StatusOK {
+			t.Errorf("expected 200, got %d", result.Response.StatusCode)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for delivery result")
+	}
+}
+
+func TestDeliveryPool_PauseHostDelaysDelivery(t *testing.T) {
+	pool, server := newTestDeliveryPool(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	httpReq, _ := http.NewRequest("GET", server.URL, nil)
+	host := httpReq.URL.Hostname()
+
+	pool.PauseHost(host, 150*time.Millisecond)
+
+	start := time.Now()
+	resultCh := pool.Enqueue(NewRequest(httpReq, "test-provider"))
+
+	select {
+	case result := <-resultCh:
+		if result.Err != nil {
+			t.Fatalf("unexpected error: %v", result.Err)
+		}
+		if time.Since(start) < 150*time.Millisecond {
+			t.Error("expected delivery to wait out the pause window")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for delivery result")
+	}
+}
+
+func TestDeliveryPool_CancelByTag(t *testing.T) {
+	pool, server := newTestDeliveryPool(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+	pool.workersPerHost = 1
+
+	httpReq, _ := http.NewRequest("GET", server.URL, nil)
+
+	// The first request is picked up by the lone worker immediately and
+	// starts sleeping in the handler; the rest sit queued behind it.
+	inFlight := NewRequest(httpReq, "keep")
+	pool.Enqueue(inFlight)
+
+	queuedReq, _ := http.NewRequest("GET", server.URL, nil)
+	queued := NewRequest(queuedReq, "stale-provider")
+	resultCh := pool.Enqueue(queued)
+
+	pool.CancelByTag("stale-provider")
+
+	select {
+	case result := <-resultCh:
+		if result.Err == nil {
+			t.Error("expected canceled request to return an error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for cancellation result")
+	}
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dryRunProvider wraps an LLMProvider and additionally writes every call's
+// rendered prompts and resulting TriageResult (or error) to dir as a
+// timestamped JSON file, so prompts/responses can be reviewed or diffed
+// offline without re-triggering the real webhook/alert pipeline. The
+// wrapped provider still runs normally - this only adds recording.
+type dryRunProvider struct {
+	inner LLMProvider
+	dir   string
+}
+
+func newDryRunProvider(inner LLMProvider, dir string) *dryRunProvider {
+	return &dryRunProvider{inner: inner, dir: dir}
+}
+
+// dryRunRecord is what gets written to disk per Complete call.
+type dryRunRecord struct {
+	Timestamp    time.Time     `json:"timestamp"`
+	SystemPrompt string        `json:"system_prompt"`
+	UserPrompt   string        `json:"user_prompt"`
+	Result       *TriageResult `json:"result,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Complete satisfies LLMProvider.
+func (p *dryRunProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, schema map[string]interface{}) (*TriageResult, error) {
+	result, err := p.inner.Complete(ctx, systemPrompt, userPrompt, schema)
+
+	record := dryRunRecord{
+		Timestamp:    time.Now().UTC(),
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Result:       result,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if recordErr := p.record(record); recordErr != nil {
+		fmt.Printf("⚠️  LLM dry-run: failed to record prompt/response: %v\n", recordErr)
+	}
+
+	return result, err
+}
+
+func (p *dryRunProvider) record(record dryRunRecord) error {
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := record.Timestamp.Format("20060102T150405.000000000") + ".json"
+	return os.WriteFile(filepath.Join(p.dir, name), data, 0o644)
+}
@@ -1,6 +1,8 @@
 package llm
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"strings"
 )
@@ -54,11 +56,39 @@ var HighRiskThreatTypes = []string{
 	"webshell",
 }
 
+// OnErrorPolicy controls what happens when LLM triage itself fails (timeout,
+// 5xx, unparseable JSON) instead of just losing the alert.
+type OnErrorPolicy string
+
+const (
+	// OnErrorPassthrough returns the underlying error to the caller
+	// unchanged - the pipeline's original behavior.
+	OnErrorPassthrough OnErrorPolicy = "passthrough"
+	// OnErrorApplyDefault runs only the deterministic post-guardrails on a
+	// minimal result derived from ThreatContext (IOC counts, DB hits).
+	OnErrorApplyDefault OnErrorPolicy = "apply_default"
+	// OnErrorEscalate synthesizes a medium-severity result flagged for
+	// analyst review, with the failure reason recorded in Analysis.
+	OnErrorEscalate OnErrorPolicy = "escalate"
+	// OnErrorSkipAlert returns a FalsePositive:false, Severity:"info" marker
+	// so orchestration can drop the alert rather than act on it.
+	OnErrorSkipAlert OnErrorPolicy = "skip_alert"
+)
+
 // GuardrailConfig controls guardrail behavior
 type GuardrailConfig struct {
 	MinConfidenceForFalsePositive int    // Minimum confidence to mark as FP (default: 85)
 	RequireThreatIntelForCritical bool   // Require threat intel match for critical severity (default: true)
 	MaxSeverityWithoutThreatIntel string // Max severity without threat intel (default: "medium")
+
+	// CTIProvider supplies real-time reputation for IOCs, supplementing (and
+	// taking priority over) the static KnownGoodIndicators/HighRiskThreatTypes
+	// lists above. Nil disables it, leaving guardrail behavior unchanged.
+	CTIProvider CTIProvider
+
+	// OnError controls recovery when LLM triage fails outright (default:
+	// OnErrorPassthrough, i.e. the error propagates to the caller as before).
+	OnError OnErrorPolicy
 }
 
 // DefaultGuardrailConfig returns the default configuration
@@ -67,20 +97,126 @@ func DefaultGuardrailConfig() GuardrailConfig {
 		MinConfidenceForFalsePositive: 85,
 		RequireThreatIntelForCritical: true,
 		MaxSeverityWithoutThreatIntel: "medium",
+		OnError:                       OnErrorPassthrough,
+	}
+}
+
+// DefaultOnError builds the TriageResult for config.OnError given reason (a
+// human-readable description of the failure - timeout, 5xx, or parse error).
+// Returns (nil, false) for
+// OnErrorPassthrough (and any unrecognized policy), signaling the caller
+// should return the original error unchanged instead.
+func DefaultOnError(ctx context.Context, threat ThreatContext, config GuardrailConfig, reason string) (*TriageResult, bool) {
+	switch config.OnError {
+	case OnErrorEscalate:
+		log.Printf("⚠️  On-error policy: escalating for analyst review (%s)", reason)
+		RecordGuardrail("on_error", "escalate")
+		return &TriageResult{
+			Severity:      "medium",
+			Priority:      3,
+			Summary:       "Triage unavailable - flagged for analyst review",
+			Analysis:      "Automated triage could not complete: " + reason + ". Flagged for manual analyst review.",
+			Recommended:   []string{"Manually review this alert", "Investigate the triage pipeline failure"},
+			FalsePositive: false,
+			Confidence:    50,
+		}, true
+
+	case OnErrorApplyDefault:
+		log.Printf("⚠️  On-error policy: applying deterministic guardrails only (%s)", reason)
+		RecordGuardrail("on_error", "apply_default")
+		result := minimalTriageResult(threat, reason)
+		return ApplyPostLLMGuardrails(ctx, result, threat, config), true
+
+	case OnErrorSkipAlert:
+		log.Printf("⚠️  On-error policy: skipping alert (%s)", reason)
+		RecordGuardrail("on_error", "skip_alert")
+		return &TriageResult{
+			Severity:      "info",
+			Priority:      5,
+			Summary:       "Triage unavailable - alert skipped",
+			Analysis:      "Automated triage could not complete: " + reason,
+			FalsePositive: false,
+			Confidence:    0,
+		}, true
+
+	default: // OnErrorPassthrough, or unset
+		return nil, false
+	}
+}
+
+// minimalTriageResult derives a starting-point TriageResult purely from
+// ThreatContext (IOC counts, DB hits) for OnErrorApplyDefault to hand to
+// ApplyPostLLMGuardrails, since there's no LLM output to validate instead.
+func minimalTriageResult(threat ThreatContext, reason string) *TriageResult {
+	iocsInDB := 0
+	for _, ioc := range threat.IOCs {
+		if ioc.InDatabase {
+			iocsInDB++
+		}
+	}
+
+	return &TriageResult{
+		Severity:      "low",
+		Priority:      4,
+		Summary:       "Automated default triage (LLM unavailable)",
+		Analysis:      fmt.Sprintf("LLM triage was unavailable (%s); this assessment is derived solely from existing threat intelligence (%d IOC(s) in database).", reason, iocsInDB),
+		FalsePositive: iocsInDB == 0,
+		Confidence:    50,
 	}
 }
 
 // ApplyPreLLMGuardrails checks if we can make a determination before calling LLM
 // Returns (result, shouldSkipLLM)
-func ApplyPreLLMGuardrails(threat ThreatContext, config GuardrailConfig) (*TriageResult, bool) {
-	// Check if all IOCs are known good
+func ApplyPreLLMGuardrails(ctx context.Context, threat ThreatContext, config GuardrailConfig) (*TriageResult, bool) {
+	// Check if all IOCs are known good, consulting the CTI provider (if
+	// configured) for anything the static allow-list doesn't already cover
 	allKnownGood := true
 	hasIOCs := len(threat.IOCs) > 0
 
+	hasHighRiskIOC := false
+	highRiskTypes := []string{}
+
 	for _, ioc := range threat.IOCs {
-		if !isKnownGoodIndicator(ioc.Value) {
+		good := isKnownGoodIndicator(ioc.Value)
+
+		verdict := lookupCTIVerdict(ctx, config.CTIProvider, ioc)
+		switch verdict.Reputation {
+		case CTIReputationBenign:
+			good = true
+		case CTIReputationMalicious:
+			good = false
+			hasHighRiskIOC = true
+			if len(verdict.ThreatCategories) > 0 {
+				highRiskTypes = append(highRiskTypes, verdict.ThreatCategories...)
+			} else {
+				highRiskTypes = append(highRiskTypes, "cti_malicious")
+			}
+
+			// CTI with concrete attack evidence (not just a bare "malicious"
+			// tag) skips the LLM entirely and goes straight to critical.
+			if len(verdict.AttackDetails) > 0 {
+				log.Printf("⚡ Pre-filter: CTI confirms active attack activity for %s: %v", ioc.Value, verdict.AttackDetails)
+				RecordGuardrail("pre", "cti_hit")
+				return &TriageResult{
+					Severity:      "critical",
+					Priority:      1,
+					Summary:       "CTI confirms active malicious attack activity",
+					Analysis:      fmt.Sprintf("External threat intelligence confirms %s is involved in active attacks: %s", ioc.Value, strings.Join(verdict.AttackDetails, ", ")),
+					Recommended:   []string{"Isolate affected endpoint immediately", "Initiate incident response procedures", "Conduct forensic analysis", "Check for lateral movement"},
+					FalsePositive: false,
+					Confidence:    95,
+				}, true
+			}
+		}
+
+		// A source recognizing this as a known crawler/scanner/VPN exit node
+		// is as good as an explicit benign reputation for our purposes.
+		if verdict.KnownService {
+			good = true
+		}
+
+		if !good {
 			allKnownGood = false
-			break
 		}
 	}
 
@@ -98,10 +234,8 @@ func ApplyPreLLMGuardrails(threat ThreatContext, config GuardrailConfig) (*Triag
 		}, true
 	}
 
-	// Check if any IOC has high-risk threat types
-	hasHighRiskIOC := false
-	highRiskTypes := []string{}
-
+	// Check if any IOC has high-risk threat types (CTI verdicts from the loop
+	// above may have already set hasHighRiskIOC)
 	for _, ioc := range threat.IOCs {
 		if ioc.InDatabase {
 			for _, threatType := range ioc.ThreatTypes {
@@ -132,7 +266,7 @@ func ApplyPreLLMGuardrails(threat ThreatContext, config GuardrailConfig) (*Triag
 }
 
 // ApplyPostLLMGuardrails validates and adjusts LLM output
-func ApplyPostLLMGuardrails(result *TriageResult, threat ThreatContext, config GuardrailConfig) *TriageResult {
+func ApplyPostLLMGuardrails(ctx context.Context, result *TriageResult, threat ThreatContext, config GuardrailConfig) *TriageResult {
 	log.Printf("🛡️  Applying post-LLM guardrails...")
 
 	// Validate and normalize fields
@@ -140,9 +274,12 @@ func ApplyPostLLMGuardrails(result *TriageResult, threat ThreatContext, config G
 	result.Priority = normalizePriority(result.Priority, result.Severity)
 	result.Confidence = normalizeConfidence(result.Confidence)
 
-	// Count IOCs in threat database
+	// Count IOCs in threat database, plus anything the CTI provider
+	// independently flags as malicious - treated as an additional
+	// corroborating source even when it's not in our own threat DB
 	iocsInDB := 0
 	hasHighRiskTypes := false
+	ctiMaliciousCount := 0
 
 	for _, ioc := range threat.IOCs {
 		if ioc.InDatabase {
@@ -153,6 +290,14 @@ func ApplyPostLLMGuardrails(result *TriageResult, threat ThreatContext, config G
 				}
 			}
 		}
+
+		if verdict := lookupCTIVerdict(ctx, config.CTIProvider, ioc); verdict.Reputation == CTIReputationMalicious {
+			hasHighRiskTypes = true
+			ctiMaliciousCount++
+			if !ioc.InDatabase {
+				iocsInDB++
+			}
+		}
 	}
 
 	// Guardrail 1: Cannot mark as false positive if IOCs are in threat database
@@ -212,6 +357,15 @@ func ApplyPostLLMGuardrails(result *TriageResult, threat ThreatContext, config G
 		}
 	}
 
+	// Guardrail 4b: CTI independently confirming malicious activity boosts
+	// confidence, distinct from Guardrail 4's multi-source corroboration -
+	// a single strong CTI hit is still worth more certainty than none.
+	if ctiMaliciousCount > 0 {
+		log.Printf("✅ Guardrail: CTI confirms malicious activity (%d IOC(s)) - boosting confidence", ctiMaliciousCount)
+		RecordGuardrail("post", "cti_confirm")
+		result.Confidence = min(result.Confidence+10, 98)
+	}
+
 	// Guardrail 5: False positive requires high confidence
 	if result.FalsePositive && result.Confidence < config.MinConfidenceForFalsePositive {
 		log.Printf("⚠️  Guardrail: False positive has low confidence (%d%% < %d%%) - marking as uncertain",
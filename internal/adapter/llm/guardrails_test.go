@@ -1,6 +1,8 @@
 package llm
 
 import (
+	"context"
+	"strings"
 	"testing"
 )
 
@@ -123,7 +125,7 @@ func TestApplyPreLLMGuardrails_AllKnownGood(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	result, shouldSkip := ApplyPreLLMGuardrails(threat, config)
+	result, shouldSkip := ApplyPreLLMGuardrails(context.Background(), threat, config)
 
 	if !shouldSkip {
 		t.Error("Expected shouldSkip=true for known good domain")
@@ -165,7 +167,7 @@ func TestApplyPreLLMGuardrails_HighRiskThreatType(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	result, shouldSkip := ApplyPreLLMGuardrails(threat, config)
+	result, shouldSkip := ApplyPreLLMGuardrails(context.Background(), threat, config)
 
 	if !shouldSkip {
 		t.Error("Expected shouldSkip=true for high-risk threat type")
@@ -205,7 +207,7 @@ func TestApplyPreLLMGuardrails_NoMatch(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	result, shouldSkip := ApplyPreLLMGuardrails(threat, config)
+	result, shouldSkip := ApplyPreLLMGuardrails(context.Background(), threat, config)
 
 	if shouldSkip {
 		t.Error("Expected shouldSkip=false for unknown domain")
@@ -238,7 +240,7 @@ func TestApplyPostLLMGuardrails_OverrideFalsePositive(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	adjusted := ApplyPostLLMGuardrails(result, threat, config)
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 
 	if adjusted.FalsePositive {
 		t.Error("Expected FalsePositive to be overridden to false")
@@ -275,7 +277,7 @@ func TestApplyPostLLMGuardrails_UpgradeSeverity(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	adjusted := ApplyPostLLMGuardrails(result, threat, config)
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 
 	if adjusted.Severity != "high" {
 		t.Errorf("Expected severity to be upgraded to high, got %s", adjusted.Severity)
@@ -311,7 +313,7 @@ func TestApplyPostLLMGuardrails_BoostConfidence(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	adjusted := ApplyPostLLMGuardrails(result, threat, config)
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 
 	if adjusted.Confidence <= 70 {
 		t.Errorf("Expected confidence to be boosted, got %d", adjusted.Confidence)
@@ -338,7 +340,7 @@ func TestApplyPostLLMGuardrails_RequireThreatIntelForCritical(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	adjusted := ApplyPostLLMGuardrails(result, threat, config)
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 
 	if adjusted.Severity == "critical" {
 		t.Error("Expected critical to be downgraded without threat intel")
@@ -363,7 +365,7 @@ func TestApplyPostLLMGuardrails_LowConfidenceFalsePositive(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	adjusted := ApplyPostLLMGuardrails(result, threat, config)
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 
 	if adjusted.FalsePositive {
 		t.Error("Expected FalsePositive to be overridden due to low confidence")
@@ -446,13 +448,88 @@ func TestApplyPostLLMGuardrails_AddDefaultRecommendations(t *testing.T) {
 	}
 
 	config := DefaultGuardrailConfig()
-	adjusted := ApplyPostLLMGuardrails(result, threat, config)
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 
 	if len(adjusted.Recommended) == 0 {
 		t.Error("Expected default recommendations to be added")
 	}
 }
 
+func TestDefaultOnError_Passthrough(t *testing.T) {
+	config := DefaultGuardrailConfig()
+	threat := ThreatContext{AlertID: "test-on-error-1"}
+
+	result, handled := DefaultOnError(context.Background(), threat, config, "upstream timeout")
+
+	if handled {
+		t.Error("Expected OnErrorPassthrough to not be handled")
+	}
+	if result != nil {
+		t.Errorf("Expected nil result for OnErrorPassthrough, got %+v", result)
+	}
+}
+
+func TestDefaultOnError_Escalate(t *testing.T) {
+	config := DefaultGuardrailConfig()
+	config.OnError = OnErrorEscalate
+	threat := ThreatContext{AlertID: "test-on-error-2"}
+
+	result, handled := DefaultOnError(context.Background(), threat, config, "upstream timeout")
+
+	if !handled {
+		t.Fatal("Expected OnErrorEscalate to be handled")
+	}
+	if result.Severity != "medium" {
+		t.Errorf("Expected medium severity, got %s", result.Severity)
+	}
+	if result.FalsePositive {
+		t.Error("Expected FalsePositive=false for escalated result")
+	}
+	if !strings.Contains(result.Analysis, "upstream timeout") {
+		t.Errorf("Expected failure reason in Analysis, got %q", result.Analysis)
+	}
+}
+
+func TestDefaultOnError_SkipAlert(t *testing.T) {
+	config := DefaultGuardrailConfig()
+	config.OnError = OnErrorSkipAlert
+	threat := ThreatContext{AlertID: "test-on-error-3"}
+
+	result, handled := DefaultOnError(context.Background(), threat, config, "unparseable JSON")
+
+	if !handled {
+		t.Fatal("Expected OnErrorSkipAlert to be handled")
+	}
+	if result.Severity != "info" {
+		t.Errorf("Expected info severity, got %s", result.Severity)
+	}
+	if result.FalsePositive {
+		t.Error("Expected FalsePositive=false for skip_alert marker")
+	}
+}
+
+func TestDefaultOnError_ApplyDefault(t *testing.T) {
+	config := DefaultGuardrailConfig()
+	config.OnError = OnErrorApplyDefault
+	threat := ThreatContext{
+		AlertID: "test-on-error-4",
+		IOCs: []IOCContext{
+			{Type: "IPV4", Value: "192.0.2.1", InDatabase: true, ThreatTypes: []string{"c2_server"}},
+		},
+	}
+
+	result, handled := DefaultOnError(context.Background(), threat, config, "5xx from LLM backend")
+
+	if !handled {
+		t.Fatal("Expected OnErrorApplyDefault to be handled")
+	}
+	// The deterministic post-guardrails should upgrade severity given the
+	// high-risk threat type, same as if the LLM itself had returned it.
+	if result.Severity != "high" {
+		t.Errorf("Expected post-guardrails to upgrade severity to high, got %s", result.Severity)
+	}
+}
+
 func BenchmarkApplyPreLLMGuardrails(b *testing.B) {
 	threat := ThreatContext{
 		AlertID:        "bench-001",
@@ -473,7 +550,96 @@ func BenchmarkApplyPreLLMGuardrails(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ApplyPreLLMGuardrails(threat, config)
+		ApplyPreLLMGuardrails(context.Background(), threat, config)
+	}
+}
+
+// stubCTIProvider returns a fixed verdict for every lookup, regardless of
+// the IOC asked about.
+type stubCTIProvider struct {
+	verdict CTIVerdict
+}
+
+func (s stubCTIProvider) LookupIOC(ctx context.Context, iocType, value string) (CTIVerdict, error) {
+	return s.verdict, nil
+}
+
+func TestApplyPreLLMGuardrails_CTIMaliciousWithAttackDetails(t *testing.T) {
+	threat := ThreatContext{
+		AlertID:    "test-cti-001",
+		ThreatName: "Inbound connection from unknown IP",
+		Endpoint:   "SERVER-02",
+		IOCs: []IOCContext{
+			{Type: "IPV4", Value: "198.51.100.7", InDatabase: false},
+		},
+	}
+
+	config := DefaultGuardrailConfig()
+	config.CTIProvider = stubCTIProvider{verdict: CTIVerdict{
+		Reputation:    CTIReputationMalicious,
+		AttackDetails: []string{"ssh_bf", "http_probing"},
+	}}
+
+	result, shouldSkip := ApplyPreLLMGuardrails(context.Background(), threat, config)
+
+	if !shouldSkip {
+		t.Fatal("Expected shouldSkip=true for CTI-confirmed attack activity")
+	}
+	if result.Severity != "critical" {
+		t.Errorf("Expected severity=critical, got %s", result.Severity)
+	}
+	if result.FalsePositive {
+		t.Error("Expected FalsePositive=false")
+	}
+}
+
+func TestApplyPreLLMGuardrails_CTIKnownService(t *testing.T) {
+	threat := ThreatContext{
+		AlertID:  "test-cti-002",
+		Endpoint: "WORKSTATION-20",
+		IOCs: []IOCContext{
+			{Type: "IPV4", Value: "203.0.113.50", InDatabase: false},
+		},
+	}
+
+	config := DefaultGuardrailConfig()
+	config.CTIProvider = stubCTIProvider{verdict: CTIVerdict{
+		Reputation:   CTIReputationUnknown,
+		KnownService: true,
+	}}
+
+	result, shouldSkip := ApplyPreLLMGuardrails(context.Background(), threat, config)
+
+	if !shouldSkip {
+		t.Fatal("Expected shouldSkip=true for a known-service IOC")
+	}
+	if !result.FalsePositive {
+		t.Error("Expected FalsePositive=true for a known crawler/scanner/VPN indicator")
+	}
+}
+
+func TestApplyPostLLMGuardrails_CTIConfirmBoostsConfidence(t *testing.T) {
+	result := &TriageResult{
+		Severity:      "high",
+		Priority:      2,
+		FalsePositive: false,
+		Confidence:    60,
+	}
+
+	threat := ThreatContext{
+		AlertID: "test-cti-003",
+		IOCs: []IOCContext{
+			{Type: "IPV4", Value: "198.51.100.7", InDatabase: false},
+		},
+	}
+
+	config := DefaultGuardrailConfig()
+	config.CTIProvider = stubCTIProvider{verdict: CTIVerdict{Reputation: CTIReputationMalicious}}
+
+	adjusted := ApplyPostLLMGuardrails(context.Background(), result, threat, config)
+
+	if adjusted.Confidence <= 60 {
+		t.Errorf("Expected confidence to be boosted by CTI confirmation, got %d", adjusted.Confidence)
 	}
 }
 
@@ -501,6 +667,6 @@ func BenchmarkApplyPostLLMGuardrails(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ApplyPostLLMGuardrails(result, threat, config)
+		ApplyPostLLMGuardrails(context.Background(), result, threat, config)
 	}
 }
@@ -8,6 +8,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// fpRateHistoryMinutes is the minimum number of one-minute buckets an
+// FPRateTracker retains, regardless of its configured window, so it can
+// always serve the 1h and 24h rates below.
+const fpRateHistoryMinutes = 24 * 60
+
 var (
 	// metricsOnce ensures metrics are registered only once
 	metricsOnce sync.Once
@@ -32,6 +37,51 @@ var (
 
 	// llmFalsePositiveRate tracks percentage of alerts marked as false positive
 	llmFalsePositiveRate prometheus.Gauge
+
+	// llmFPRate1h and llmFPRate24h track the false-positive rate over
+	// fixed trailing windows, independent of the configurable window
+	// backing llmFalsePositiveRate
+	llmFPRate1h  prometheus.Gauge
+	llmFPRate24h prometheus.Gauge
+
+	// llmFPRateThresholdExceededTotal counts how many times the 1h FP rate
+	// has crossed fpRateAlertThreshold, for alerting on LLM prompt drift or
+	// upstream feed poisoning
+	llmFPRateThresholdExceededTotal prometheus.Counter
+
+	// defaultFPTracker accumulates per-minute (total, fp) counts so the
+	// rates above can be recomputed on a ticker instead of requiring
+	// callers to track the window themselves
+	defaultFPTracker *FPRateTracker
+
+	// fpRateAlertThreshold is the 1h FP rate above which
+	// llmFPRateThresholdExceededTotal increments, set from
+	// LLM_FP_RATE_ALERT_THRESHOLD
+	fpRateAlertThreshold float64
+
+	// llmDeliveryQueueDepth tracks pending requests per host in DeliveryPool
+	llmDeliveryQueueDepth *prometheus.GaugeVec
+
+	// llmDeliveryInFlight tracks in-progress worker deliveries per host in DeliveryPool
+	llmDeliveryInFlight *prometheus.GaugeVec
+
+	// llmHTTPRequestDuration tracks raw HTTP round-trip latency to LLM
+	// backends, by host and status, separate from end-to-end triage latency
+	llmHTTPRequestDuration *prometheus.HistogramVec
+
+	// llmCTIHitsTotal tracks CachingCTIProvider cache hits
+	llmCTIHitsTotal prometheus.Counter
+
+	// llmCTIMissesTotal tracks CachingCTIProvider cache misses (upstream was called)
+	llmCTIMissesTotal prometheus.Counter
+
+	// llmCTIErrorsTotal tracks failed CTIProvider lookups
+	llmCTIErrorsTotal prometheus.Counter
+
+	// llmCallDuration tracks end-to-end latency of LLMTriager.callProvider,
+	// including retries/backoff inside the ResilientClient - wider and
+	// coarser than llmHTTPRequestDuration's per-attempt timing
+	llmCallDuration prometheus.Histogram
 )
 
 // InitMetrics registers all Prometheus metrics for LLM triaging
@@ -92,9 +142,203 @@ func InitMetrics() {
 				Help: "Percentage of alerts marked as false positive",
 			},
 		)
+
+		llmFPRate1h = promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "llm_fp_rate_1h",
+				Help: "False positive rate over the trailing 1 hour",
+			},
+		)
+
+		llmFPRate24h = promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "llm_fp_rate_24h",
+				Help: "False positive rate over the trailing 24 hours",
+			},
+		)
+
+		llmFPRateThresholdExceededTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "llm_fp_rate_threshold_exceeded_total",
+				Help: "Total number of times the 1h false positive rate crossed LLM_FP_RATE_ALERT_THRESHOLD",
+			},
+		)
+
+		llmDeliveryQueueDepth = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "llm_delivery_queue_depth",
+				Help: "Number of pending requests queued per host in the delivery pool",
+			},
+			[]string{"host"},
+		)
+
+		llmDeliveryInFlight = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "llm_delivery_in_flight",
+				Help: "Number of in-progress deliveries per host in the delivery pool",
+			},
+			[]string{"host"},
+		)
+
+		llmHTTPRequestDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "llm_http_request_duration_seconds",
+				Help:    "Duration of individual HTTP round trips to LLM backends, by host and status",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"host", "status"},
+		)
+
+		llmCTIHitsTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "cti_hits",
+				Help: "Total number of CTI provider lookups served from cache",
+			},
+		)
+
+		llmCTIMissesTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "cti_misses",
+				Help: "Total number of CTI provider lookups that required an upstream call",
+			},
+		)
+
+		llmCTIErrorsTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "cti_errors",
+				Help: "Total number of failed CTI provider lookups",
+			},
+		)
+
+		llmCallDuration = promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "llm_call_duration_seconds",
+				Help:    "End-to-end duration of a single LLMTriager.callProvider invocation, including retries",
+				Buckets: []float64{0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 20.0, 30.0},
+			},
+		)
+
+		fpRateWindow := time.Duration(getEnvInt("LLM_FP_RATE_WINDOW_MINUTES", 60)) * time.Minute
+		fpRateAlertThreshold = getEnvFloat("LLM_FP_RATE_ALERT_THRESHOLD", 0.25)
+		defaultFPTracker = NewFPRateTracker(fpRateWindow)
+		go runFPRateUpdater(defaultFPTracker, fpRateWindow)
 	})
 }
 
+// FPRateTracker maintains a ring buffer of per-minute (total, fp) triage
+// counts, so the false-positive rate over any trailing window up to its
+// capacity can be recomputed without callers tracking the window
+// themselves. Capacity is at least fpRateHistoryMinutes regardless of the
+// configured window, so it can always serve the 1h/24h rates runFPRateUpdater
+// publishes alongside the windowed rate.
+type FPRateTracker struct {
+	mu      sync.Mutex
+	buckets []struct{ total, fp int }
+	minute  int64 // unix minute of buckets[cursor]
+	cursor  int
+	window  time.Duration
+}
+
+// NewFPRateTracker returns a tracker whose history covers window (e.g. the
+// 60-minute default UpdateFalsePositiveRate is refreshed with on every tick),
+// or fpRateHistoryMinutes if that is longer. Rate takes its window on every
+// call; window here only sizes the buffer.
+func NewFPRateTracker(window time.Duration) *FPRateTracker {
+	capacity := int(window / time.Minute)
+	if capacity < fpRateHistoryMinutes {
+		capacity = fpRateHistoryMinutes
+	}
+	return &FPRateTracker{
+		buckets: make([]struct{ total, fp int }, capacity),
+		minute:  time.Now().Unix() / 60,
+		window:  window,
+	}
+}
+
+// Record tallies one triage result into the current minute's bucket.
+func (f *FPRateTracker) Record(isFP bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.advanceLocked(time.Now().Unix() / 60)
+	f.buckets[f.cursor].total++
+	if isFP {
+		f.buckets[f.cursor].fp++
+	}
+}
+
+// Rate returns the false-positive rate over the trailing window, as a
+// fraction in [0, 1], clamped to the tracker's retained history. It returns
+// 0 if no alerts were recorded in that window.
+func (f *FPRateTracker) Rate(window time.Duration) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.advanceLocked(time.Now().Unix() / 60)
+
+	minutes := int(window / time.Minute)
+	if minutes <= 0 {
+		minutes = 1
+	}
+	if minutes > len(f.buckets) {
+		minutes = len(f.buckets)
+	}
+
+	var total, fp int
+	idx := f.cursor
+	for i := 0; i < minutes; i++ {
+		total += f.buckets[idx].total
+		fp += f.buckets[idx].fp
+		idx--
+		if idx < 0 {
+			idx = len(f.buckets) - 1
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(fp) / float64(total)
+}
+
+// advanceLocked rotates the ring buffer forward to the minute now, zeroing
+// any minutes that elapsed with no Record call. f.mu must be held.
+func (f *FPRateTracker) advanceLocked(now int64) {
+	elapsed := now - f.minute
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > int64(len(f.buckets)) {
+		elapsed = int64(len(f.buckets))
+	}
+	for i := int64(0); i < elapsed; i++ {
+		f.cursor = (f.cursor + 1) % len(f.buckets)
+		f.buckets[f.cursor] = struct{ total, fp int }{}
+	}
+	f.minute = now
+}
+
+// runFPRateUpdater recomputes the false-positive rate every minute from
+// tracker and publishes it to llmFalsePositiveRate (over window) plus the
+// fixed 1h/24h gauges, incrementing llmFPRateThresholdExceededTotal whenever
+// the 1h rate is above fpRateAlertThreshold.
+func runFPRateUpdater(tracker *FPRateTracker, window time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		UpdateFalsePositiveRate(tracker.Rate(window))
+
+		rate1h := tracker.Rate(time.Hour)
+		rate24h := tracker.Rate(24 * time.Hour)
+		if llmFPRate1h != nil {
+			llmFPRate1h.Set(rate1h)
+		}
+		if llmFPRate24h != nil {
+			llmFPRate24h.Set(rate24h)
+		}
+		if llmFPRateThresholdExceededTotal != nil && rate1h > fpRateAlertThreshold {
+			llmFPRateThresholdExceededTotal.Inc()
+		}
+	}
+}
+
 // RecordTriageRequest records a triage request with status and reason
 // status: "success", "error", "skipped"
 // reason: "pre_filter", "llm", "error", "timeout", etc.
@@ -144,12 +388,16 @@ func RecordResult(result *TriageResult) {
 		llmTriageSeverity.WithLabelValues(result.Severity).Inc()
 	}
 
-	// Note: False positive rate is calculated separately as it requires
-	// tracking total alerts vs FP alerts over a time window
-	// For now, we track individual FP results via the request counter
+	// Feed the sliding-window FP rate tracker; runFPRateUpdater reads it
+	// back every minute into llmFalsePositiveRate/llmFPRate1h/llmFPRate24h.
+	if defaultFPTracker != nil {
+		defaultFPTracker.Record(result.FalsePositive)
+	}
 }
 
-// RecordFalsePositive records when an alert is marked as a false positive
+// RecordFalsePositive records when an alert is marked as a false positive.
+// Callers pair this with RecordResult for the same result, which already
+// feeds the FP rate tracker - this only increments the labeled counter.
 func RecordFalsePositive() {
 	// This increments a counter that can be used to calculate FP rate
 	if llmTriageRequestsTotal != nil {
@@ -166,6 +414,57 @@ func UpdateFalsePositiveRate(rate float64) {
 	}
 }
 
+// RecordQueueDepth records the current pending-request count for a host's
+// delivery queue
+func RecordQueueDepth(host string, depth int) {
+	if llmDeliveryQueueDepth != nil {
+		llmDeliveryQueueDepth.WithLabelValues(host).Set(float64(depth))
+	}
+}
+
+// RecordInFlight records the current number of deliveries being worked for a host
+func RecordInFlight(host string, count int) {
+	if llmDeliveryInFlight != nil {
+		llmDeliveryInFlight.WithLabelValues(host).Set(float64(count))
+	}
+}
+
+// RecordHTTPRequest records the duration of one HTTP round trip to an LLM
+// backend, labeled by host and status ("200", "429", "error", ...).
+func RecordHTTPRequest(host, status string, duration time.Duration) {
+	if llmHTTPRequestDuration != nil {
+		llmHTTPRequestDuration.WithLabelValues(host, status).Observe(duration.Seconds())
+	}
+}
+
+// RecordCTIHit records a CTIProvider lookup served from cache.
+func RecordCTIHit() {
+	if llmCTIHitsTotal != nil {
+		llmCTIHitsTotal.Inc()
+	}
+}
+
+// RecordCTIMiss records a CTIProvider lookup that required an upstream call.
+func RecordCTIMiss() {
+	if llmCTIMissesTotal != nil {
+		llmCTIMissesTotal.Inc()
+	}
+}
+
+// RecordCTIError records a failed CTIProvider lookup.
+func RecordCTIError() {
+	if llmCTIErrorsTotal != nil {
+		llmCTIErrorsTotal.Inc()
+	}
+}
+
+// RecordCallDuration records one callProvider invocation's end-to-end latency.
+func RecordCallDuration(duration time.Duration) {
+	if llmCallDuration != nil {
+		llmCallDuration.Observe(duration.Seconds())
+	}
+}
+
 // TriageTimer is a helper for timing triage operations
 type TriageTimer struct {
 	start time.Time
@@ -154,6 +154,35 @@ func TestUpdateFalsePositiveRate(t *testing.T) {
 	}
 }
 
+func TestFPRateTrackerRate(t *testing.T) {
+	tracker := NewFPRateTracker(60 * time.Minute)
+
+	if rate := tracker.Rate(time.Hour); rate != 0 {
+		t.Fatalf("expected 0 rate with no records, got %v", rate)
+	}
+
+	for i := 0; i < 3; i++ {
+		tracker.Record(false)
+	}
+	tracker.Record(true)
+
+	if rate := tracker.Rate(time.Hour); rate != 0.25 {
+		t.Fatalf("expected 0.25 rate, got %v", rate)
+	}
+	if rate := tracker.Rate(24 * time.Hour); rate != 0.25 {
+		t.Fatalf("expected same 0.25 rate over the wider 24h window, got %v", rate)
+	}
+}
+
+func TestFPRateTrackerMinimumHistory(t *testing.T) {
+	// Even a tracker configured with a short window must retain at least
+	// fpRateHistoryMinutes of buckets so the 1h/24h gauges stay accurate.
+	tracker := NewFPRateTracker(5 * time.Minute)
+	if len(tracker.buckets) < fpRateHistoryMinutes {
+		t.Fatalf("expected at least %d buckets, got %d", fpRateHistoryMinutes, len(tracker.buckets))
+	}
+}
+
 func TestTriageTimer(t *testing.T) {
 	InitMetrics()
 
@@ -0,0 +1,391 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// requestIDHeader carries the correlation ID RequestIDMiddleware injects,
+// which recordError (resilient_client.go) reads back off the request to
+// stamp into error logs across retries.
+const requestIDHeader = "X-Request-ID"
+
+type requestIDContextKeyType struct{}
+
+var requestIDContextKey = requestIDContextKeyType{}
+
+// WithRequestID attaches a caller-supplied correlation ID to ctx, so
+// RequestIDMiddleware propagates it instead of generating a new one.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// RequestIDFromContext returns the correlation ID attached via
+// WithRequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey).(string)
+	return id, ok
+}
+
+// Middleware wraps an http.RoundTripper with cross-cutting behavior (auth,
+// headers, logging, ...), in the style of net/http server middleware
+// chains, but on the client side of the transport.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// Use composes mw around the client's current transport and returns c, so
+// new backends can layer on auth/headers/logging without touching the
+// circuit breaker, retry, or rate-limit logic in resilient_client.go. The
+// first middleware given is outermost (it sees the request first and the
+// response last), matching router.Use ordering.
+func (c *ResilientClient) Use(mw ...Middleware) *ResilientClient {
+	transport := c.client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		transport = mw[i](transport)
+	}
+	c.client.Transport = transport
+	return c
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper, mirroring
+// http.HandlerFunc on the server side.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// RequestIDMiddleware injects X-Request-ID into every outgoing request,
+// reusing the ID from ctx (set via WithRequestID) if the caller already
+// has one, and generating a fresh UUID otherwise. The same ID is sent on
+// every retry attempt, so recordError's logs correlate across them.
+func RequestIDMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			id, ok := RequestIDFromContext(req.Context())
+			if !ok || id == "" {
+				id = uuid.New().String()
+			}
+			req.Header.Set(requestIDHeader, id)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// AuthMode selects how AuthMiddleware authenticates outgoing requests.
+type AuthMode string
+
+const (
+	AuthModeBearer AuthMode = "bearer"
+	AuthModeAPIKey AuthMode = "api_key"
+	AuthModeSigV4  AuthMode = "sigv4"
+)
+
+// AuthConfig configures AuthMiddleware for one of three schemes: a static
+// Bearer token (OpenAI/Anthropic-style), an API-key header (Azure OpenAI's
+// api-key), or AWS SigV4 request signing (Bedrock-style endpoints).
+type AuthConfig struct {
+	Mode AuthMode
+
+	// Token is the Bearer token or API key value. HeaderName names the
+	// header for AuthModeAPIKey; it defaults to "Api-Key".
+	Token      string
+	HeaderName string
+
+	// RefreshToken, if set, is called to obtain a fresh Bearer token when a
+	// request comes back 401. The refreshed token is cached and reused
+	// until the next 401, and the failed request is retried once with it
+	// in the same RoundTrip — the resilience core's own retry loop never
+	// has to know a refresh happened.
+	RefreshToken func(ctx context.Context) (string, error)
+
+	// SigV4 holds the AWS credentials and scope used when Mode is
+	// AuthModeSigV4.
+	SigV4 SigV4Config
+}
+
+// SigV4Config holds the AWS credentials and signing scope AuthMiddleware
+// needs to sign requests for Bedrock-style endpoints.
+type SigV4Config struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Service         string // e.g. "bedrock"
+}
+
+// AuthMiddleware authenticates outgoing requests per cfg.Mode.
+func AuthMiddleware(cfg AuthConfig) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &authRoundTripper{cfg: cfg, next: next, token: cfg.Token}
+	}
+}
+
+type authRoundTripper struct {
+	cfg   AuthConfig
+	next  http.RoundTripper
+	mu    sync.Mutex
+	token string
+}
+
+func (a *authRoundTripper) currentToken() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.token
+}
+
+func (a *authRoundTripper) setToken(token string) {
+	a.mu.Lock()
+	a.token = token
+	a.mu.Unlock()
+}
+
+func (a *authRoundTripper) sign(req *http.Request) error {
+	switch a.cfg.Mode {
+	case AuthModeBearer:
+		req.Header.Set("Authorization", "Bearer "+a.currentToken())
+	case AuthModeAPIKey:
+		header := a.cfg.HeaderName
+		if header == "" {
+			header = "Api-Key"
+		}
+		req.Header.Set(header, a.currentToken())
+	case AuthModeSigV4:
+		return signSigV4(req, a.cfg.SigV4)
+	}
+	return nil
+}
+
+func (a *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Buffer the body so it can be resent if a 401 triggers a token
+	// refresh and retry below.
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	}
+
+	if err := a.sign(req); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := a.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized ||
+		a.cfg.Mode != AuthModeBearer || a.cfg.RefreshToken == nil {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	token, refreshErr := a.cfg.RefreshToken(req.Context())
+	if refreshErr != nil {
+		return nil, fmt.Errorf("token refresh failed after 401: %w", refreshErr)
+	}
+	a.setToken(token)
+
+	retryReq := req.Clone(req.Context())
+	if bodyBytes != nil {
+		retryReq.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	}
+	if err := a.sign(retryReq); err != nil {
+		return nil, fmt.Errorf("failed to sign retried request: %w", err)
+	}
+	return a.next.RoundTrip(retryReq)
+}
+
+// signSigV4 signs req in place using AWS Signature Version 4, the scheme
+// Bedrock-style endpoints require in place of a bearer token.
+func signSigV4(req *http.Request, cfg SigV4Config) error {
+	service := cfg.Service
+	if service == "" {
+		service = "bedrock"
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read body for signing: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		bodyBytes = b
+	}
+	payloadHash := sha256Hex(bodyBytes)
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cfg.SessionToken)
+	}
+	if req.Host == "" {
+		req.Host = req.URL.Host
+	}
+
+	canonicalHeaders, signedHeaders := canonicalizeHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	credentialScope := fmt.Sprintf("%s/%s/%s/aws4_request", dateStamp, cfg.Region, service)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := sigV4SigningKey(cfg.SecretAccessKey, dateStamp, cfg.Region, service)
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, credentialScope, signedHeaders, signature,
+	))
+
+	return nil
+}
+
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	return u.EscapedPath()
+}
+
+// canonicalizeHeaders builds the canonical-headers block and signed-headers
+// list SigV4 requires: every header lower-cased, sorted, and colon-joined.
+func canonicalizeHeaders(req *http.Request) (canonical, signed string) {
+	values := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		values[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var cb strings.Builder
+	for _, k := range keys {
+		cb.WriteString(k)
+		cb.WriteString(":")
+		cb.WriteString(strings.TrimSpace(values[k]))
+		cb.WriteString("\n")
+	}
+	return cb.String(), strings.Join(keys, ";")
+}
+
+func sigV4SigningKey(secret, dateStamp, region, service string) []byte {
+	kDate := hmacSHA256([]byte("AWS4"+secret), dateStamp)
+	kRegion := hmacSHA256(kDate, region)
+	kService := hmacSHA256(kRegion, service)
+	return hmacSHA256(kService, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ProviderConfig holds per-host extra headers, a custom User-Agent, and a
+// request timeout, applied by ProviderMiddleware.
+type ProviderConfig struct {
+	Headers   map[string]string
+	UserAgent string
+	Timeout   time.Duration
+}
+
+// ProviderMiddleware applies per-provider headers, User-Agent, and timeout
+// based on the outgoing request's hostname, keyed from configs. This lets
+// one ResilientClient serve several LLM backends (OpenAI, Anthropic,
+// Bedrock, Azure OpenAI), each with their own QoS needs, without branching
+// in the resilience core.
+func ProviderMiddleware(configs map[string]ProviderConfig) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			cfg, ok := configs[req.URL.Hostname()]
+			if !ok {
+				return next.RoundTrip(req)
+			}
+
+			for k, v := range cfg.Headers {
+				req.Header.Set(k, v)
+			}
+			if cfg.UserAgent != "" {
+				req.Header.Set("User-Agent", cfg.UserAgent)
+			}
+			if cfg.Timeout > 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), cfg.Timeout)
+				// cancel is left to a timer rather than deferred, so the
+				// response body stays readable after RoundTrip returns.
+				time.AfterFunc(cfg.Timeout, cancel)
+				req = req.WithContext(ctx)
+			}
+
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// LoggingMiddleware times each round trip and records it against the
+// llm_http_request_duration_seconds metric, labeled by host and status, so
+// dashboards can separate raw backend latency from end-to-end triage
+// latency (RecordTriageDuration).
+func LoggingMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			elapsed := time.Since(start)
+
+			status := "error"
+			if resp != nil {
+				status = strconv.Itoa(resp.StatusCode)
+			}
+
+			id := req.Header.Get(requestIDHeader)
+			log.Printf("🌐 %s %s request_id=%s status=%s duration=%s", req.Method, req.URL.Host, id, status, elapsed)
+			RecordHTTPRequest(req.URL.Host, status, elapsed)
+
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResilientClient_Use_OrdersMiddlewareOutermostFirst(t *testing.T) {
+	var order []string
+
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewResilientClient(5*time.Second, ResilientClientConfig{}).Use(tag("outer"), tag("inner"))
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("expected middleware to run outer, then inner, got %v", order)
+	}
+}
+
+func TestRequestIDMiddleware_GeneratesAndPropagatesID(t *testing.T) {
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get(requestIDHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewResilientClient(5*time.Second, ResilientClientConfig{}).Use(RequestIDMiddleware())
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotHeader == "" {
+		t.Error("expected a generated X-Request-ID header")
+	}
+
+	ctx := WithRequestID(context.Background(), "fixed-id")
+	req2, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if _, err := client.Do(req2); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotHeader != "fixed-id" {
+		t.Errorf("expected propagated request ID 'fixed-id', got %q", gotHeader)
+	}
+}
+
+func TestAuthMiddleware_BearerAndAPIKey(t *testing.T) {
+	var gotAuth, gotAPIKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotAPIKey = r.Header.Get("X-Api-Key")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	bearerClient := NewResilientClient(5*time.Second, ResilientClientConfig{}).
+		Use(AuthMiddleware(AuthConfig{Mode: AuthModeBearer, Token: "tok-123"}))
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := bearerClient.Do(req); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotAuth != "Bearer tok-123" {
+		t.Errorf("expected Bearer auth header, got %q", gotAuth)
+	}
+
+	apiKeyClient := NewResilientClient(5*time.Second, ResilientClientConfig{}).
+		Use(AuthMiddleware(AuthConfig{Mode: AuthModeAPIKey, Token: "key-456", HeaderName: "X-Api-Key"}))
+	req2, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := apiKeyClient.Do(req2); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotAPIKey != "key-456" {
+		t.Errorf("expected API key header, got %q", gotAPIKey)
+	}
+}
+
+func TestAuthMiddleware_RefreshesTokenOn401(t *testing.T) {
+	var seenTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		seenTokens = append(seenTokens, token)
+		if token != "fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	refreshCalls := 0
+	client := NewResilientClient(5*time.Second, ResilientClientConfig{}).Use(AuthMiddleware(AuthConfig{
+		Mode:  AuthModeBearer,
+		Token: "stale-token",
+		RefreshToken: func(ctx context.Context) (string, error) {
+			refreshCalls++
+			return "fresh-token", nil
+		},
+	}))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if refreshCalls != 1 {
+		t.Errorf("expected exactly one refresh call, got %d", refreshCalls)
+	}
+	if len(seenTokens) != 2 || seenTokens[0] != "stale-token" || seenTokens[1] != "fresh-token" {
+		t.Errorf("expected [stale-token fresh-token], got %v", seenTokens)
+	}
+}
+
+func TestProviderMiddleware_AppliesConfigForMatchingHost(t *testing.T) {
+	var gotHeader, gotUA string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Provider-Extra")
+		gotUA = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+	client := NewResilientClient(5*time.Second, ResilientClientConfig{}).Use(ProviderMiddleware(map[string]ProviderConfig{
+		serverURL.Hostname(): {
+			Headers:   map[string]string{"X-Provider-Extra": "bedrock"},
+			UserAgent: "watchtower-llm/1.0",
+		},
+	}))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotHeader != "bedrock" {
+		t.Errorf("expected per-provider header to be applied, got %q", gotHeader)
+	}
+	if gotUA != "watchtower-llm/1.0" {
+		t.Errorf("expected per-provider User-Agent to be applied, got %q", gotUA)
+	}
+}
+
+func TestSignSigV4_SetsAuthorizationHeader(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://bedrock-runtime.us-east-1.amazonaws.com/model/invoke", strings.NewReader(`{}`))
+
+	err := signSigV4(req, SigV4Config{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		Region:          "us-east-1",
+		Service:         "bedrock",
+	})
+	if err != nil {
+		t.Fatalf("signSigV4 returned error: %v", err)
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+		t.Errorf("unexpected Authorization header: %q", auth)
+	}
+	if req.Header.Get("X-Amz-Date") == "" {
+		t.Error("expected X-Amz-Date header to be set")
+	}
+}
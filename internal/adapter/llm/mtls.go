@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// MTLSConfig configures mutual TLS for ResilientClient's outbound
+// connection to a self-hosted LLM gateway (vLLM, llama.cpp server, Ollama
+// behind an ingress), as an alternative to AuthMiddleware's bearer token.
+type MTLSConfig struct {
+	ClientCertFile string // LLM_CLIENT_CERT
+	ClientKeyFile  string // LLM_CLIENT_KEY
+	CABundleFile   string // LLM_CA_BUNDLE, optional: falls back to the system root pool when unset
+	ServerName     string // LLM_TLS_SERVER_NAME, optional: for endpoints whose cert CN/SAN doesn't match the dialed host
+}
+
+// MTLSConfigFromEnv builds an MTLSConfig from LLM_CLIENT_CERT,
+// LLM_CLIENT_KEY, LLM_CA_BUNDLE, and LLM_TLS_SERVER_NAME. Returns (nil, nil)
+// when neither cert env var is set, so callers fall back cleanly to
+// bearer-token auth.
+func MTLSConfigFromEnv() (*MTLSConfig, error) {
+	certFile := os.Getenv("LLM_CLIENT_CERT")
+	keyFile := os.Getenv("LLM_CLIENT_KEY")
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("LLM_CLIENT_CERT and LLM_CLIENT_KEY must both be set")
+	}
+
+	return &MTLSConfig{
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+		CABundleFile:   os.Getenv("LLM_CA_BUNDLE"),
+		ServerName:     os.Getenv("LLM_TLS_SERVER_NAME"),
+	}, nil
+}
+
+// mtlsCertReloader holds the client certificate newMTLSTransport loaded from
+// disk, re-readable via Reload so a rotated cert takes effect without
+// restarting the triager - in-flight connections keep whatever cert they
+// already negotiated with; only future handshakes pick up the reload.
+type mtlsCertReloader struct {
+	certFile, keyFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+func newMTLSCertReloader(certFile, keyFile string) (*mtlsCertReloader, error) {
+	r := &mtlsCertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the certificate/key pair from disk.
+func (r *mtlsCertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load LLM client certificate: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate, handing
+// out whatever certificate is current at handshake time.
+func (r *mtlsCertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// watchSIGHUP reloads r whenever the process receives SIGHUP - the
+// conventional "reload config/certs" signal, e.g. from cert-manager's
+// renewal hook or an operator's `kill -HUP`.
+func (r *mtlsCertReloader) watchSIGHUP() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			if err := r.Reload(); err != nil {
+				fmt.Printf("⚠️  failed to reload LLM client certificate on SIGHUP: %v\n", err)
+			} else {
+				fmt.Printf("🔐 reloaded LLM client certificate %s on SIGHUP\n", r.certFile)
+			}
+		}
+	}()
+}
+
+// watchFile polls certFile/keyFile's mtimes every interval and reloads r
+// when either changes, for deployments (e.g. a mounted Kubernetes Secret)
+// where SIGHUP never reaches this process directly.
+func (r *mtlsCertReloader) watchFile(interval time.Duration) {
+	go func() {
+		lastCert, _ := os.Stat(r.certFile)
+		lastKey, _ := os.Stat(r.keyFile)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			certInfo, err := os.Stat(r.certFile)
+			if err != nil {
+				continue
+			}
+			keyInfo, err := os.Stat(r.keyFile)
+			if err != nil {
+				continue
+			}
+
+			changed := lastCert == nil || lastKey == nil ||
+				!certInfo.ModTime().Equal(lastCert.ModTime()) ||
+				!keyInfo.ModTime().Equal(lastKey.ModTime())
+			if !changed {
+				continue
+			}
+
+			if err := r.Reload(); err != nil {
+				fmt.Printf("⚠️  failed to reload LLM client certificate: %v\n", err)
+				continue
+			}
+			fmt.Printf("🔐 reloaded LLM client certificate %s (changed on disk)\n", r.certFile)
+			lastCert, lastKey = certInfo, keyInfo
+		}
+	}()
+}
+
+// mtlsFileWatchInterval is how often watchFile polls cert/key mtimes as a
+// SIGHUP fallback.
+const mtlsFileWatchInterval = 30 * time.Second
+
+// newMTLSTransport builds an http.RoundTripper that presents cfg's client
+// certificate (re-read on SIGHUP or when changed on disk) and, when
+// cfg.CABundleFile is set, verifies the server against that CA bundle
+// instead of the system root pool.
+func newMTLSTransport(cfg MTLSConfig) (http.RoundTripper, error) {
+	reloader, err := newMTLSCertReloader(cfg.ClientCertFile, cfg.ClientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	reloader.watchSIGHUP()
+	reloader.watchFile(mtlsFileWatchInterval)
+
+	tlsConfig := &tls.Config{
+		GetClientCertificate: reloader.GetClientCertificate,
+		ServerName:           cfg.ServerName,
+	}
+
+	if cfg.CABundleFile != "" {
+		caBytes, err := os.ReadFile(cfg.CABundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read LLM CA bundle: %w", err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caBytes) {
+			return nil, fmt.Errorf("no valid certificates found in %s", cfg.CABundleFile)
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
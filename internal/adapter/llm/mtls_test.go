@@ -0,0 +1,238 @@
+package llm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mtlsTestCA is a minimal self-signed CA used to issue both the server and
+// client leaf certificates in TestTriageWithMTLS, mirroring how an operator
+// would run their own internal CA for a self-hosted LLM gateway.
+type mtlsTestCA struct {
+	cert    *x509.Certificate
+	certDER []byte
+	key     *ecdsa.PrivateKey
+}
+
+func newMTLSTestCA(t *testing.T) *mtlsTestCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "watchtower-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+
+	return &mtlsTestCA{cert: cert, certDER: der, key: key}
+}
+
+func (ca *mtlsTestCA) pemBytes() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certDER})
+}
+
+// issueLeaf signs a leaf certificate for cn, valid for serverAuth and/or
+// clientAuth as given by extKeyUsage.
+func (ca *mtlsTestCA) issueLeaf(t *testing.T, cn string, extKeyUsage []x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate %s key: %v", cn, err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("failed to generate serial number: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  extKeyUsage,
+		DNSNames:     []string{"127.0.0.1", "localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("failed to issue %s certificate: %v", cn, err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal %s key: %v", cn, err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("failed to build %s key pair: %v", cn, err)
+	}
+	return pair
+}
+
+func writePEMFiles(t *testing.T, dir, name string, pair tls.Certificate) (certFile, keyFile string) {
+	t.Helper()
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: pair.Certificate[0]})
+	keyDER, err := x509.MarshalECPrivateKey(pair.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("failed to marshal %s key: %v", name, err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatalf("failed to write %s cert: %v", name, err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("failed to write %s key: %v", name, err)
+	}
+	return certFile, keyFile
+}
+
+func TestTriageWithMTLS(t *testing.T) {
+	ca := newMTLSTestCA(t)
+	dir := t.TempDir()
+
+	serverCert := ca.issueLeaf(t, "llm-gateway", []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	clientCert := ca.issueLeaf(t, "watchtower-triager", []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+	clientCertFile, clientKeyFile := writePEMFiles(t, dir, "client", clientCert)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, ca.pemBytes(), 0600); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"severity\":\"low\",\"priority\":4,\"summary\":\"ok\",\"analysis\":\"ok\",\"recommended\":[],\"false_positive\":false,\"confidence\":60}"}}]}`))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	mtlsConfig := MTLSConfig{
+		ClientCertFile: clientCertFile,
+		ClientKeyFile:  clientKeyFile,
+		CABundleFile:   caFile,
+		ServerName:     "localhost",
+	}
+
+	config := ResilientClientConfig{EnableCircuitBreaker: false, MaxRetries: 0}
+	config.MTLS = &mtlsConfig
+
+	triager := &LLMTriager{
+		apiURL:  server.URL,
+		apiKey:  "unused-when-mtls",
+		model:   "gpt-4o-mini",
+		client:  NewResilientClient(5*time.Second, config),
+		enabled: true,
+	}
+
+	threat := ThreatContext{
+		AlertID: "TEST-MTLS-001",
+		IOCs: []IOCContext{
+			{Type: "DOMAIN", Value: "unknown-mtls-test-domain.xyz", InDatabase: false},
+		},
+	}
+
+	result, err := triager.Triage(context.Background(), threat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+	if result.Severity != "low" {
+		t.Errorf("expected severity=low, got %s", result.Severity)
+	}
+}
+
+func TestMTLSConfigFromEnv_Unset(t *testing.T) {
+	t.Setenv("LLM_CLIENT_CERT", "")
+	t.Setenv("LLM_CLIENT_KEY", "")
+
+	cfg, err := MTLSConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != nil {
+		t.Errorf("expected nil config when unset, got %+v", cfg)
+	}
+}
+
+func TestMTLSConfigFromEnv_PartiallySet(t *testing.T) {
+	t.Setenv("LLM_CLIENT_CERT", "/tmp/cert.pem")
+	t.Setenv("LLM_CLIENT_KEY", "")
+
+	if _, err := MTLSConfigFromEnv(); err == nil {
+		t.Error("expected an error when only LLM_CLIENT_CERT is set")
+	}
+}
+
+func TestMTLSCertReloader_Reload(t *testing.T) {
+	ca := newMTLSTestCA(t)
+	dir := t.TempDir()
+	pair := ca.issueLeaf(t, "reload-test", []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+	certFile, keyFile := writePEMFiles(t, dir, "reload", pair)
+
+	reloader, err := newMTLSCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cert, err := reloader.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cert == nil {
+		t.Fatal("expected a non-nil certificate")
+	}
+
+	if err := reloader.Reload(); err != nil {
+		t.Errorf("unexpected error reloading unchanged certificate: %v", err)
+	}
+}
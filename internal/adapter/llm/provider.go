@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// LLMProvider abstracts the wire format of a specific LLM backend (OpenAI,
+// Anthropic, Ollama, ...) behind a single structured-output call, so
+// LLMTriager doesn't have to special-case response_format vs. Ollama's
+// format:"json" vs. scraping JSON out of a markdown fence by hand.
+type LLMProvider interface {
+	// Complete sends systemPrompt/userPrompt to the backend, constrained by
+	// schema (a JSON Schema describing TriageResult's LLM-facing fields),
+	// and decodes the result directly into a TriageResult.
+	Complete(ctx context.Context, systemPrompt, userPrompt string, schema map[string]interface{}) (*TriageResult, error)
+}
+
+// triageResultJSONSchema is the JSON Schema for TriageResult's LLM-facing
+// fields. Tags/Queue are populated by the rules engine after the LLM call
+// (see rules.go), not by the model, so they're intentionally excluded.
+// Providers that support structured output (OpenAI's response_format,
+// Ollama's format) are given this schema so the model is constrained to
+// emit exactly this shape.
+var triageResultJSONSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"severity":       map[string]interface{}{"type": "string", "enum": []string{"critical", "high", "medium", "low", "info"}},
+		"priority":       map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 5},
+		"summary":        map[string]interface{}{"type": "string"},
+		"analysis":       map[string]interface{}{"type": "string"},
+		"recommended":    map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"false_positive": map[string]interface{}{"type": "boolean"},
+		"confidence":     map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 100},
+	},
+	"required":             []string{"severity", "priority", "summary", "analysis", "recommended", "false_positive", "confidence"},
+	"additionalProperties": false,
+}
+
+// ProviderFromEnv selects an LLMProvider by LLM_PROVIDER ("openai",
+// "anthropic", "ollama"; defaults to "openai" for anything else), each
+// defaulting its API URL so LLM_API_URL only needs to be set to override
+// it, and wraps the result in a dry-run recorder when LLM_DRY_RUN is set.
+func ProviderFromEnv(client *ResilientClient, apiKey, model string) LLMProvider {
+	var provider LLMProvider
+	switch strings.ToLower(os.Getenv("LLM_PROVIDER")) {
+	case "anthropic":
+		provider = NewAnthropicProvider(client, apiURLFromEnv("https://api.anthropic.com/v1/messages"), apiKey, model)
+	case "ollama":
+		provider = NewOllamaProvider(client, apiURLFromEnv("http://localhost:11434/api/chat"), model)
+	default:
+		provider = NewOpenAIProvider(client, apiURLFromEnv("https://api.openai.com/v1/chat/completions"), apiKey, model)
+	}
+
+	if os.Getenv("LLM_DRY_RUN") == "true" {
+		dir := os.Getenv("LLM_DRY_RUN_DIR")
+		if dir == "" {
+			dir = "llm_dryrun"
+		}
+		provider = newDryRunProvider(provider, dir)
+	}
+
+	return provider
+}
+
+func apiURLFromEnv(fallback string) string {
+	if url := os.Getenv("LLM_API_URL"); url != "" {
+		return url
+	}
+	return fallback
+}
@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// anthropicAPIVersion is the Messages API version this provider speaks;
+// bump alongside any request/response shape changes below.
+const anthropicAPIVersion = "2023-06-01"
+
+// AnthropicProvider talks to Anthropic's /v1/messages endpoint.
+type AnthropicProvider struct {
+	apiURL string
+	apiKey string
+	model  string
+	client *ResilientClient
+}
+
+func NewAnthropicProvider(client *ResilientClient, apiURL, apiKey, model string) *AnthropicProvider {
+	if model == "" {
+		model = "claude-3-5-haiku-20241022"
+	}
+	return &AnthropicProvider{apiURL: apiURL, apiKey: apiKey, model: model, client: client}
+}
+
+// Complete satisfies LLMProvider. Anthropic has no response_format knob, so
+// structured output is forced via a single forced tool call: schema becomes
+// the tool's input_schema, and tool_choice pins the model to calling it.
+func (p *AnthropicProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, schema map[string]interface{}) (*TriageResult, error) {
+	const toolName = "submit_triage_result"
+
+	requestBody := map[string]interface{}{
+		"model":      p.model,
+		"max_tokens": 1000,
+		"system":     systemPrompt,
+		"messages": []map[string]string{
+			{"role": "user", "content": userPrompt},
+		},
+		"tools": []map[string]interface{}{
+			{
+				"name":         toolName,
+				"description": "Submit the structured triage assessment for this alert.",
+				"input_schema": schema,
+			},
+		},
+		"tool_choice": map[string]interface{}{"type": "tool", "name": toolName},
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("LLM API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, block := range response.Content {
+		if block.Type != "tool_use" || block.Name != toolName {
+			continue
+		}
+		var result TriageResult
+		if err := json.Unmarshal(block.Input, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse tool input: %w", err)
+		}
+		return &result, nil
+	}
+
+	return nil, fmt.Errorf("no %s tool call in Anthropic response", toolName)
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OllamaProvider talks to a local (or self-hosted) Ollama server's
+// /api/chat endpoint, for triaging without sending alert data to a
+// third-party API.
+type OllamaProvider struct {
+	apiURL string
+	model  string
+	client *ResilientClient
+}
+
+func NewOllamaProvider(client *ResilientClient, apiURL, model string) *OllamaProvider {
+	if model == "" {
+		model = "llama3.1"
+	}
+	return &OllamaProvider{apiURL: apiURL, model: model, client: client}
+}
+
+// Complete satisfies LLMProvider. Ollama's "format" field accepts either
+// the literal string "json" or a full JSON Schema (supported since Ollama
+// 0.5); schema is passed through directly so the server enforces it.
+func (p *OllamaProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, schema map[string]interface{}) (*TriageResult, error) {
+	requestBody := map[string]interface{}{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": userPrompt},
+		},
+		"stream": false,
+	}
+	if schema != nil {
+		requestBody["format"] = schema
+	} else {
+		requestBody["format"] = "json"
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("LLM API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return decodeTriageResult(response.Message.Content)
+}
@@ -0,0 +1,116 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAIProvider talks to an OpenAI-compatible /v1/chat/completions
+// endpoint - OpenAI itself, or any LiteLLM/vLLM proxy exposing the same
+// shape.
+type OpenAIProvider struct {
+	apiURL string
+	apiKey string
+	model  string
+	client *ResilientClient
+}
+
+func NewOpenAIProvider(client *ResilientClient, apiURL, apiKey, model string) *OpenAIProvider {
+	return &OpenAIProvider{apiURL: apiURL, apiKey: apiKey, model: model, client: client}
+}
+
+// Complete satisfies LLMProvider. When schema is non-nil it's attached as
+// response_format: {"type":"json_schema",...} so the model is constrained
+// to schema's shape; decodeTriageResult still strips a markdown fence as a
+// fallback for backends that ignore response_format.
+func (p *OpenAIProvider) Complete(ctx context.Context, systemPrompt, userPrompt string, schema map[string]interface{}) (*TriageResult, error) {
+	requestBody := map[string]interface{}{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": userPrompt},
+		},
+		"temperature": 0.3, // Lower temperature for more consistent analysis
+		"max_tokens":  1000,
+	}
+	if schema != nil {
+		requestBody["response_format"] = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   "triage_result",
+				"schema": schema,
+				"strict": true,
+			},
+		}
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("LLM API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in LLM response")
+	}
+
+	return decodeTriageResult(response.Choices[0].Message.Content)
+}
+
+// decodeTriageResult parses content into a TriageResult, stripping a
+// markdown code fence first if one is present. Providers that honor
+// structured output shouldn't need the fence-stripping, but it's cheap
+// insurance against a backend that ignores it and replies conversationally.
+func decodeTriageResult(content string) (*TriageResult, error) {
+	jsonStr := strings.TrimSpace(content)
+	if idx := strings.Index(jsonStr, "```json"); idx != -1 {
+		jsonStr = jsonStr[idx+7:]
+		if endIdx := strings.Index(jsonStr, "```"); endIdx != -1 {
+			jsonStr = jsonStr[:endIdx]
+		}
+	} else if idx := strings.Index(jsonStr, "```"); idx != -1 {
+		jsonStr = jsonStr[idx+3:]
+		if endIdx := strings.Index(jsonStr, "```"); endIdx != -1 {
+			jsonStr = jsonStr[:endIdx]
+		}
+	}
+	jsonStr = strings.TrimSpace(jsonStr)
+
+	var result TriageResult
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w (response: %s)", err, jsonStr)
+	}
+	return &result, nil
+}
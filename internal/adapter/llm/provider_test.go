@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeTriageResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  bool
+	}{
+		{
+			name: "Valid JSON in markdown",
+			response: "```json\n" +
+				`{"severity":"high","priority":2,"summary":"Test","analysis":"Test analysis","recommended":["Action 1"],"false_positive":false,"confidence":85}` +
+				"\n```",
+			wantErr: false,
+		},
+		{
+			name:     "Valid JSON without markdown",
+			response: `{"severity":"medium","priority":3,"summary":"Test","analysis":"Test","recommended":[],"false_positive":false,"confidence":70}`,
+			wantErr:  false,
+		},
+		{
+			name:     "Invalid JSON",
+			response: "not a valid json",
+			wantErr:  true,
+		},
+		{
+			name: "JSON with extra text",
+			response: "Here is my analysis:\n```json\n" +
+				`{"severity":"low","priority":4,"summary":"Test","analysis":"Test","recommended":[],"false_positive":true,"confidence":90}` +
+				"\n```\nHope this helps!",
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := decodeTriageResult(tt.response)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+
+			if result == nil {
+				t.Error("Expected non-nil result")
+				return
+			}
+
+			if result.Severity == "" {
+				t.Error("Expected severity to be set")
+			}
+			if result.Summary == "" {
+				t.Error("Expected summary to be set")
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeTriageResult(b *testing.B) {
+	response := `{"severity":"high","priority":2,"summary":"Test","analysis":"Test analysis","recommended":["Action 1","Action 2"],"false_positive":false,"confidence":85}`
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		decodeTriageResult(response)
+	}
+}
+
+func TestOpenAIProviderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := ResilientClientConfig{
+		EnableCircuitBreaker: false,
+		MaxRetries:           0,
+		InitialInterval:      100 * time.Millisecond,
+		MaxInterval:          1 * time.Second,
+	}
+	provider := NewOpenAIProvider(NewResilientClient(100*time.Millisecond, config), server.URL, "test-key", "gpt-4o-mini")
+
+	ctx := context.Background()
+	_, err := provider.Complete(ctx, "system", "test prompt", triageResultJSONSchema)
+
+	if err == nil {
+		t.Error("Expected timeout error")
+	}
+}
+
+func TestOpenAIProviderErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal server error"))
+	}))
+	defer server.Close()
+
+	config := ResilientClientConfig{
+		EnableCircuitBreaker: false,
+		MaxRetries:           0,
+		InitialInterval:      100 * time.Millisecond,
+		MaxInterval:          1 * time.Second,
+	}
+	provider := NewOpenAIProvider(NewResilientClient(5*time.Second, config), server.URL, "test-key", "gpt-4o-mini")
+
+	ctx := context.Background()
+	_, err := provider.Complete(ctx, "system", "test prompt", triageResultJSONSchema)
+
+	if err == nil {
+		t.Error("Expected error for 500 status code")
+	}
+	if !strings.Contains(err.Error(), "500") {
+		t.Errorf("Expected error message to contain status code, got: %v", err)
+	}
+}
+
+func TestProviderFromEnvSelectsBackend(t *testing.T) {
+	config := DefaultResilientClientConfig()
+	client := NewResilientClient(5*time.Second, config)
+
+	t.Setenv("LLM_PROVIDER", "anthropic")
+	if _, ok := ProviderFromEnv(client, "key", "model").(*AnthropicProvider); !ok {
+		t.Error("Expected AnthropicProvider when LLM_PROVIDER=anthropic")
+	}
+
+	t.Setenv("LLM_PROVIDER", "ollama")
+	if _, ok := ProviderFromEnv(client, "", "model").(*OllamaProvider); !ok {
+		t.Error("Expected OllamaProvider when LLM_PROVIDER=ollama")
+	}
+
+	t.Setenv("LLM_PROVIDER", "")
+	if _, ok := ProviderFromEnv(client, "key", "model").(*OpenAIProvider); !ok {
+		t.Error("Expected OpenAIProvider by default")
+	}
+}
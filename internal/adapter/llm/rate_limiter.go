@@ -0,0 +1,166 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// minHostRateLimitQPS floors hostRateLimiter.onThrottled's multiplicative
+// decrease so a run of 429s can't halve the rate all the way to a standstill.
+const minHostRateLimitQPS = 0.1
+
+// rateLimitRecoveryStreak is how many consecutive 2xx responses
+// hostRateLimiter.onSuccess requires before nudging the fill rate back up,
+// so recovery follows sustained good behavior rather than a single lucky
+// response.
+const rateLimitRecoveryStreak = 10
+
+// rateLimitRecoveryStep is the fraction of ceilingQPS each recovery nudge
+// adds, the additive-increase half of the AIMD throttle.
+const rateLimitRecoveryStep = 0.1
+
+// hostRateLimiter is a per-host token bucket whose fill rate adapts to the
+// upstream's own signals: a 429/503 halves it (multiplicative decrease),
+// and rateLimitRecoveryStreak consecutive 2xx responses raise it back
+// towards the configured ceiling in small steps (additive increase) - the
+// same AIMD shape TCP congestion control uses, so a client stays under
+// whatever rate an upstream is actually willing to serve instead of the
+// operator having to retune RateLimitQPS by hand.
+type hostRateLimiter struct {
+	mu            sync.Mutex
+	qps           float64
+	ceilingQPS    float64
+	burst         float64
+	tokens        float64
+	lastRefill    time.Time
+	consecutiveOK int
+}
+
+func newHostRateLimiter(qps float64, burst int) *hostRateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &hostRateLimiter{
+		qps:        qps,
+		ceilingQPS: qps,
+		burst:      float64(burst),
+		tokens:     float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available (or ctx is done), then consumes one.
+func (l *hostRateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		l.refillLocked()
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.qps * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *hostRateLimiter) refillLocked() {
+	now := time.Now()
+	elapsed := now.Sub(l.lastRefill).Seconds()
+	l.lastRefill = now
+
+	l.tokens += elapsed * l.qps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// onThrottled applies the multiplicative-decrease half of the AIMD throttle
+// after a 429/503 from this host.
+func (l *hostRateLimiter) onThrottled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.qps /= 2
+	if l.qps < minHostRateLimitQPS {
+		l.qps = minHostRateLimitQPS
+	}
+	l.consecutiveOK = 0
+}
+
+// onSuccess applies the additive-increase half: once rateLimitRecoveryStreak
+// consecutive 2xx responses land, the fill rate steps back towards
+// ceilingQPS instead of staying throttled forever.
+func (l *hostRateLimiter) onSuccess() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.qps >= l.ceilingQPS {
+		l.consecutiveOK = 0
+		return
+	}
+
+	l.consecutiveOK++
+	if l.consecutiveOK < rateLimitRecoveryStreak {
+		return
+	}
+	l.consecutiveOK = 0
+
+	l.qps += l.ceilingQPS * rateLimitRecoveryStep
+	if l.qps > l.ceilingQPS {
+		l.qps = l.ceilingQPS
+	}
+}
+
+// currentQPS reports the fill rate in effect right now, for Stats().
+func (l *hostRateLimiter) currentQPS() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.qps
+}
+
+// hostRateLimiters is a set of hostRateLimiter keyed by request host,
+// created lazily so a ResilientClient only pays for a bucket per upstream it
+// actually talks to.
+type hostRateLimiters struct {
+	mu       sync.Mutex
+	qps      float64
+	burst    int
+	limiters map[string]*hostRateLimiter
+}
+
+func newHostRateLimiters(qps float64, burst int) *hostRateLimiters {
+	return &hostRateLimiters{qps: qps, burst: burst, limiters: make(map[string]*hostRateLimiter)}
+}
+
+func (h *hostRateLimiters) forHost(host string) *hostRateLimiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if l, ok := h.limiters[host]; ok {
+		return l
+	}
+	l := newHostRateLimiter(h.qps, h.burst)
+	h.limiters[host] = l
+	return l
+}
+
+// snapshot returns the current fill rate of every host this client has seen,
+// for Stats().
+func (h *hostRateLimiters) snapshot() map[string]float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := make(map[string]float64, len(h.limiters))
+	for host, l := range h.limiters {
+		out[host] = l.currentQPS()
+	}
+	return out
+}
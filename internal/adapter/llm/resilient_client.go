@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -17,9 +19,11 @@ import (
 
 // ResilientClient wraps an HTTP client with circuit breaker and retry logic
 type ResilientClient struct {
-	client  *http.Client
-	breaker *gobreaker.CircuitBreaker
-	config  ResilientClientConfig
+	client       *http.Client
+	breaker      *gobreaker.CircuitBreaker
+	config       ResilientClientConfig
+	rateLimiters *hostRateLimiters // nil unless RateLimitQPS is set and no SharedStateBackend
+	inFlight     int64
 }
 
 // ResilientClientConfig holds configuration for the resilient client
@@ -33,6 +37,23 @@ type ResilientClientConfig struct {
 	MaxRetries      int
 	InitialInterval time.Duration
 	MaxInterval     time.Duration
+
+	// SharedStateBackend, when set, moves circuit-breaker and rate-limit
+	// state out of process (e.g. into Redis) so multiple watchtower
+	// replicas share one view of an upstream instead of each rediscovering
+	// outages independently. ProviderName keys that shared state; it
+	// defaults to "llm-api" when empty. Leave SharedStateBackend nil to use
+	// the in-process gobreaker above.
+	SharedStateBackend SharedStateBackend
+	ProviderName       string
+	RateLimitQPS       float64
+	RateLimitBurst     int
+
+	// MTLS, when set, makes the underlying http.Transport present a client
+	// certificate (and verify the server against a custom CA bundle) for
+	// self-hosted LLM gateways that require mutual TLS instead of - or
+	// alongside - a bearer token. Nil leaves the default transport in place.
+	MTLS *MTLSConfig
 }
 
 // DefaultResilientClientConfig returns default configuration values
@@ -53,6 +74,15 @@ func NewResilientClient(timeout time.Duration, config ResilientClientConfig) *Re
 		Timeout: timeout,
 	}
 
+	if config.MTLS != nil {
+		transport, err := newMTLSTransport(*config.MTLS)
+		if err != nil {
+			fmt.Printf("⚠️  LLM mTLS configured but unusable, falling back to the default transport: %v\n", err)
+		} else {
+			client.Transport = transport
+		}
+	}
+
 	var breaker *gobreaker.CircuitBreaker
 	if config.EnableCircuitBreaker {
 		settings := gobreaker.Settings{
@@ -73,15 +103,72 @@ func NewResilientClient(timeout time.Duration, config ResilientClientConfig) *Re
 		breaker = gobreaker.NewCircuitBreaker(settings)
 	}
 
+	var rateLimiters *hostRateLimiters
+	if config.SharedStateBackend == nil && config.RateLimitQPS > 0 {
+		rateLimiters = newHostRateLimiters(config.RateLimitQPS, config.RateLimitBurst)
+	}
+
 	return &ResilientClient{
-		client:  client,
-		breaker: breaker,
-		config:  config,
+		client:       client,
+		breaker:      breaker,
+		config:       config,
+		rateLimiters: rateLimiters,
 	}
 }
 
+// ResilientClientStats is a point-in-time snapshot of a ResilientClient's
+// adaptive state, for callers exposing it on a debug/health endpoint.
+type ResilientClientStats struct {
+	// RateLimitQPS is the current fill rate per host this client has sent a
+	// request to; a host throttled down by repeated 429s reads below the
+	// configured RateLimitQPS until it recovers. Empty when no local rate
+	// limiter is configured (disabled, or rate limiting is delegated to a
+	// SharedStateBackend instead).
+	RateLimitQPS map[string]float64
+	// InFlight is the number of requests currently in Do, across all hosts.
+	InFlight int64
+	// CircuitState is the gobreaker state ("closed", "half-open", "open"),
+	// or "disabled" when EnableCircuitBreaker is false, or "shared" when a
+	// SharedStateBackend owns breaker state instead of the in-process one.
+	CircuitState string
+}
+
+// Stats reports the client's current rate limit, in-flight count, and
+// circuit state, for health/debug endpoints.
+func (c *ResilientClient) Stats() ResilientClientStats {
+	stats := ResilientClientStats{InFlight: atomic.LoadInt64(&c.inFlight)}
+
+	if c.rateLimiters != nil {
+		stats.RateLimitQPS = c.rateLimiters.snapshot()
+	}
+
+	switch {
+	case c.config.SharedStateBackend != nil:
+		stats.CircuitState = "shared"
+	case c.breaker != nil:
+		stats.CircuitState = c.breaker.State().String()
+	default:
+		stats.CircuitState = "disabled"
+	}
+
+	return stats
+}
+
+// providerName returns the key used to namespace shared breaker/rate-limit
+// state, defaulting to "llm-api" to match the in-process breaker's name.
+func (c *ResilientClient) providerName() string {
+	if c.config.ProviderName != "" {
+		return c.config.ProviderName
+	}
+	return "llm-api"
+}
+
 // Do executes an HTTP request with circuit breaker and retry logic
 func (c *ResilientClient) Do(req *http.Request) (*http.Response, error) {
+	if c.config.SharedStateBackend != nil {
+		return c.doWithSharedBreaker(req)
+	}
+
 	// If circuit breaker is disabled, just do the request with retry
 	if c.breaker == nil {
 		return c.doWithRetry(req)
@@ -94,7 +181,7 @@ func (c *ResilientClient) Do(req *http.Request) (*http.Response, error) {
 
 	if err != nil {
 		if errors.Is(err, gobreaker.ErrOpenState) {
-			RecordError("circuit_open")
+			c.recordError(req, "circuit_open")
 			return nil, fmt.Errorf("circuit breaker is open: %w", err)
 		}
 		return nil, err
@@ -103,6 +190,134 @@ func (c *ResilientClient) Do(req *http.Request) (*http.Response, error) {
 	return result.(*http.Response), nil
 }
 
+// recordError logs the error alongside the request's X-Request-ID (set by
+// RequestIDMiddleware, if wired in via Use) before recording the Prometheus
+// metric, so every retry of the same logical request correlates in logs.
+func (c *ResilientClient) recordError(req *http.Request, errorType string) {
+	if id := req.Header.Get(requestIDHeader); id != "" {
+		log.Printf("⚠️  LLM API error request_id=%s type=%s", id, errorType)
+	}
+	RecordError(errorType)
+}
+
+// doWithSharedBreaker executes req through a Redis-backed (or other shared)
+// circuit breaker: every replica consults the same key before dispatch, and
+// only one replica at a time probes an upstream coming back from Open.
+func (c *ResilientClient) doWithSharedBreaker(req *http.Request) (*http.Response, error) {
+	backend := c.config.SharedStateBackend
+	key := c.providerName()
+	ctx := req.Context()
+
+	allowed, err := backend.AllowRequest(ctx, key)
+	if err != nil {
+		c.recordError(req, "shared_state")
+		return nil, fmt.Errorf("shared circuit breaker unavailable: %w", err)
+	}
+	if !allowed {
+		c.recordError(req, "circuit_open")
+		return nil, fmt.Errorf("circuit breaker is open for %s", key)
+	}
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		if recErr := backend.RecordFailure(ctx, key); recErr != nil {
+			c.recordError(req, "shared_state")
+		}
+		return nil, err
+	}
+
+	if recErr := backend.RecordSuccess(ctx, key); recErr != nil {
+		c.recordError(req, "shared_state")
+	}
+	return resp, nil
+}
+
+// checkRateLimit consults the shared token-bucket rate limiter, when
+// configured, so all replicas stay within one global QPS budget per
+// provider. It is a no-op when no SharedStateBackend or QPS is configured.
+func (c *ResilientClient) checkRateLimit(ctx context.Context) error {
+	if c.config.SharedStateBackend == nil || c.config.RateLimitQPS <= 0 {
+		return nil
+	}
+
+	allowed, err := c.config.SharedStateBackend.AllowRate(ctx, c.providerName(), c.config.RateLimitQPS, c.config.RateLimitBurst)
+	if err != nil {
+		return fmt.Errorf("shared rate limit check failed: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("shared rate limit exceeded for %s", c.providerName())
+	}
+	return nil
+}
+
+// localRateLimitWait blocks on this request's host's local token bucket,
+// when one is configured (RateLimitQPS set and no SharedStateBackend - that
+// case uses checkRateLimit's shared bucket instead).
+func (c *ResilientClient) localRateLimitWait(ctx context.Context, req *http.Request) error {
+	if c.rateLimiters == nil {
+		return nil
+	}
+	return c.rateLimiters.forHost(req.URL.Host).Wait(ctx)
+}
+
+// recordRateLimitOutcome feeds a response's outcome back into this request's
+// host's local token bucket, driving the AIMD adjustment: 429/503 halves the
+// fill rate, 2xx nudges it back up. A no-op when no local limiter is
+// configured.
+func (c *ResilientClient) recordRateLimitOutcome(req *http.Request, resp *http.Response) {
+	if c.rateLimiters == nil || resp == nil {
+		return
+	}
+	limiter := c.rateLimiters.forHost(req.URL.Host)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		limiter.onThrottled()
+	case resp.StatusCode < 300:
+		limiter.onSuccess()
+	}
+}
+
+// retryAfterDuration reads a Retry-After header (either delay-seconds or an
+// HTTP-date), reporting ok=false when absent or unparseable so the caller
+// falls back to its own backoff schedule.
+func retryAfterDuration(resp *http.Response) (wait time.Duration, ok bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// retryAfterBackOff lets doWithRetry's operation override the next wait with
+// an upstream's Retry-After value, instead of always falling through to the
+// wrapped exponential schedule.
+type retryAfterBackOff struct {
+	backoff.BackOff
+	override time.Duration
+}
+
+func (b *retryAfterBackOff) NextBackOff() time.Duration {
+	// Always consult the underlying backoff first so it keeps counting
+	// against MaxRetries even on attempts where Retry-After overrides the
+	// wait; only its backoff.Stop takes priority over an override.
+	next := b.BackOff.NextBackOff()
+	if next == backoff.Stop {
+		return backoff.Stop
+	}
+	if b.override > 0 {
+		d := b.override
+		b.override = 0
+		return d
+	}
+	return next
+}
+
 // doWithRetry executes an HTTP request with exponential backoff retry logic
 func (c *ResilientClient) doWithRetry(req *http.Request) (*http.Response, error) {
 	var resp *http.Response
@@ -110,14 +325,25 @@ func (c *ResilientClient) doWithRetry(req *http.Request) (*http.Response, error)
 
 	// If max retries is 0, just do a single attempt
 	if c.config.MaxRetries == 0 {
+		if err := c.checkRateLimit(req.Context()); err != nil {
+			c.recordError(req, "rate_limit")
+			return nil, err
+		}
+		if err := c.localRateLimitWait(req.Context(), req); err != nil {
+			return nil, err
+		}
+
+		atomic.AddInt64(&c.inFlight, 1)
 		resp, err := c.client.Do(req)
+		atomic.AddInt64(&c.inFlight, -1)
 		if err != nil {
-			RecordError("connection")
+			c.recordError(req, "connection")
 			return nil, err
 		}
+		c.recordRateLimitOutcome(req, resp)
 		// Check for error status codes
 		if resp.StatusCode >= 400 {
-			c.recordErrorFromResponse(resp)
+			c.recordErrorFromResponse(req, resp)
 			resp.Body.Close()
 			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 		}
@@ -131,14 +357,23 @@ func (c *ResilientClient) doWithRetry(req *http.Request) (*http.Response, error)
 	expBackoff.Multiplier = 2.0
 	expBackoff.MaxElapsedTime = 0 // No max elapsed time, only max retries
 
-	// Wrap with max retries
-	retryBackoff := backoff.WithMaxRetries(expBackoff, uint64(c.config.MaxRetries))
+	// Wrap with max retries, then let the operation override the interval
+	// with Retry-After when the upstream sends one
+	retryBackoff := &retryAfterBackOff{BackOff: backoff.WithMaxRetries(expBackoff, uint64(c.config.MaxRetries))}
 
 	// Create a context-aware backoff
 	ctx := req.Context()
-	retryBackoff = backoff.WithContext(retryBackoff, ctx)
+	ctxBackoff := backoff.WithContext(retryBackoff, ctx)
 
 	operation := func() error {
+		if err := c.checkRateLimit(ctx); err != nil {
+			c.recordError(req, "rate_limit")
+			return err // Retry: the shared budget may free up next interval
+		}
+		if err := c.localRateLimitWait(ctx, req); err != nil {
+			return backoff.Permanent(err)
+		}
+
 		// Clone request body for retry (if present)
 		var bodyBytes []byte
 		if req.Body != nil {
@@ -156,28 +391,36 @@ func (c *ResilientClient) doWithRetry(req *http.Request) (*http.Response, error)
 		}
 
 		var err error
+		atomic.AddInt64(&c.inFlight, 1)
 		resp, err = c.client.Do(req)
+		atomic.AddInt64(&c.inFlight, -1)
 		if err != nil {
 			lastErr = err
 			if c.shouldRetry(err, nil) {
-				RecordError("connection")
+				c.recordError(req, "connection")
 				return err // Retry
 			}
-			RecordError("connection")
+			c.recordError(req, "connection")
 			return backoff.Permanent(err) // Don't retry
 		}
+		c.recordRateLimitOutcome(req, resp)
 
 		// Check if response indicates we should retry
 		if c.shouldRetry(nil, resp) {
 			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
-			c.recordErrorFromResponse(resp)
+			c.recordErrorFromResponse(req, resp)
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+				if wait, ok := retryAfterDuration(resp); ok {
+					retryBackoff.override = wait
+				}
+			}
 			resp.Body.Close()
 			return lastErr // Retry
 		}
 
 		// Success - record any API errors (e.g., 4xx)
 		if resp.StatusCode >= 400 {
-			c.recordErrorFromResponse(resp)
+			c.recordErrorFromResponse(req, resp)
 			lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 			return backoff.Permanent(lastErr) // Don't retry 4xx
 		}
@@ -185,7 +428,7 @@ func (c *ResilientClient) doWithRetry(req *http.Request) (*http.Response, error)
 		return nil
 	}
 
-	err := backoff.Retry(operation, retryBackoff)
+	err := backoff.Retry(operation, ctxBackoff)
 	if err != nil {
 		return nil, fmt.Errorf("request failed after retries: %w", lastErr)
 	}
@@ -226,25 +469,25 @@ func (c *ResilientClient) shouldRetry(err error, resp *http.Response) bool {
 }
 
 // recordErrorFromResponse records the appropriate error metric based on response status
-func (c *ResilientClient) recordErrorFromResponse(resp *http.Response) {
+func (c *ResilientClient) recordErrorFromResponse(req *http.Request, resp *http.Response) {
 	if resp == nil {
 		return
 	}
 
 	switch resp.StatusCode {
 	case http.StatusUnauthorized, http.StatusForbidden:
-		RecordError("auth")
+		c.recordError(req, "auth")
 	case http.StatusTooManyRequests:
-		RecordError("rate_limit")
+		c.recordError(req, "rate_limit")
 	case http.StatusRequestTimeout:
-		RecordError("timeout")
+		c.recordError(req, "timeout")
 	case http.StatusInternalServerError,
 		http.StatusBadGateway,
 		http.StatusServiceUnavailable,
 		http.StatusGatewayTimeout:
-		RecordError("server_error")
+		c.recordError(req, "server_error")
 	default:
-		RecordError("http_error")
+		c.recordError(req, "http_error")
 	}
 }
 
@@ -267,3 +510,13 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvFloat reads a float64 from environment variable or returns default
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hive-corporation/watchtower/internal/core/rules"
+)
+
+// evaluateRules runs t.rulesEngine (if configured) against every IOC in
+// threat, merging each IOC's Decision into one so a single alert's rule
+// hits compose instead of the last IOC silently overwriting the first's
+// match. Returns a zero Decision (no effect) if no rules engine is
+// configured.
+func (t *LLMTriager) evaluateRules(threat ThreatContext) rules.Decision {
+	var decision rules.Decision
+	if t.rulesEngine == nil {
+		return decision
+	}
+
+	threatEnv := map[string]interface{}{
+		"alert_id":       threat.AlertID,
+		"threat_name":    threat.ThreatName,
+		"classification": threat.Classification,
+		"endpoint":       threat.Endpoint,
+		"os_type":        threat.OSType,
+	}
+
+	for _, ioc := range threat.IOCs {
+		env := map[string]interface{}{
+			"ioc":    ioc,
+			"threat": threatEnv,
+			"count":  ruleCount,
+		}
+		decision.Merge(t.rulesEngine.EvaluateIOC(ioc.Value, env))
+	}
+
+	return decision
+}
+
+// ruleCount is the "count" helper available to rule expressions for a bare
+// length check (e.g. count(ioc.Sources) >= 2), since expr-lang's own count()
+// builtin takes a predicate rather than just a slice.
+func ruleCount(v interface{}) int {
+	switch s := v.(type) {
+	case []string:
+		return len(s)
+	default:
+		return 0
+	}
+}
+
+// applyRuleDecision folds d onto result: SetSeverity/RouteToQueue override,
+// ForceFalsePositive/Tag accumulate, and the matched rule names are
+// appended to Analysis for an audit trail. A Decision with no matched rules
+// leaves result unchanged.
+func applyRuleDecision(result *TriageResult, d rules.Decision) *TriageResult {
+	if len(d.MatchedRules) == 0 {
+		return result
+	}
+
+	if d.Severity != "" {
+		result.Severity = d.Severity
+	}
+	if d.ForceFalsePositive {
+		result.FalsePositive = true
+	}
+	if d.Queue != "" {
+		result.Queue = d.Queue
+	}
+	result.Tags = append(result.Tags, d.Tags...)
+	result.Analysis += fmt.Sprintf(" (rules matched: %s)", strings.Join(d.MatchedRules, ", "))
+
+	return result
+}
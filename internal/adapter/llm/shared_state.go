@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SharedStateBackend lets ResilientClient's circuit breaker and rate limiter
+// be backed by shared state instead of living entirely in-process, so
+// multiple watchtower replicas agree on whether an upstream is open and
+// share a single QPS budget per provider instead of each rediscovering
+// outages (and retrying) independently.
+type SharedStateBackend interface {
+	// AllowRequest reports whether the caller may dispatch a request for key
+	// right now, consulting shared circuit-breaker state. While the breaker
+	// is half-open, only one caller across all replicas is granted the
+	// probe request; the rest are blocked until it resolves.
+	AllowRequest(ctx context.Context, key string) (bool, error)
+	// RecordSuccess resets the shared breaker for key back to closed.
+	RecordSuccess(ctx context.Context, key string) error
+	// RecordFailure increments the shared consecutive-failure count for key,
+	// tripping the breaker open once it reaches the configured threshold.
+	RecordFailure(ctx context.Context, key string) error
+	// AllowRate consults a shared token bucket for key, refilling at qps
+	// tokens/sec up to burst capacity, and reports whether a token was available.
+	AllowRate(ctx context.Context, key string, qps float64, burst int) (bool, error)
+}
+
+// SharedStateBackendFromEnv builds a SharedStateBackend from
+// LLM_RESILIENT_STATE_BACKEND / LLM_RESILIENT_REDIS_URL, returning a nil
+// backend (and nil error) when the backend env var is unset so callers fall
+// back cleanly to the in-process gobreaker.
+func SharedStateBackendFromEnv() (SharedStateBackend, error) {
+	if os.Getenv("LLM_RESILIENT_STATE_BACKEND") != "redis" {
+		return nil, nil
+	}
+
+	redisURL := os.Getenv("LLM_RESILIENT_REDIS_URL")
+	if redisURL == "" {
+		return nil, fmt.Errorf("LLM_RESILIENT_REDIS_URL is required when LLM_RESILIENT_STATE_BACKEND=redis")
+	}
+
+	maxFailures := int64(getEnvInt("LLM_CIRCUIT_BREAKER_MAX_FAILURES", 5))
+	openTimeout := time.Duration(getEnvInt("LLM_CIRCUIT_BREAKER_TIMEOUT_SECONDS", 30)) * time.Second
+
+	return NewRedisSharedStateBackend(redisURL, maxFailures, openTimeout)
+}
+
+// RedisSharedStateBackend implements SharedStateBackend on top of
+// github.com/redis/go-redis/v9, using Lua scripts so the read-modify-write
+// breaker and rate-limiter transitions stay atomic across replicas.
+type RedisSharedStateBackend struct {
+	rdb         *redis.Client
+	maxFailures int64
+	openTimeout time.Duration
+}
+
+// NewRedisSharedStateBackend connects to redisURL (a redis:// connection
+// string as accepted by redis.ParseURL) and returns a backend that trips its
+// shared breaker after maxFailures consecutive failures, reopening for a
+// probe after openTimeout.
+func NewRedisSharedStateBackend(redisURL string, maxFailures int64, openTimeout time.Duration) (*RedisSharedStateBackend, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LLM_RESILIENT_REDIS_URL: %w", err)
+	}
+
+	return &RedisSharedStateBackend{
+		rdb:         redis.NewClient(opts),
+		maxFailures: maxFailures,
+		openTimeout: openTimeout,
+	}, nil
+}
+
+const breakerKeyPrefix = "watchtower:breaker:"
+const rateLimitKeyPrefix = "watchtower:ratelimit:"
+
+// Breaker states stored in the shared hash's "state" field.
+const (
+	breakerStateClosed   = "0"
+	breakerStateOpen     = "1"
+	breakerStateHalfOpen = "2"
+)
+
+var allowRequestScript = redis.NewScript(`
+local state = redis.call('HGET', KEYS[1], 'state')
+if not state or state == '0' then
+  return 1
+end
+
+if state == '1' then
+  local opened_at = tonumber(redis.call('HGET', KEYS[1], 'opened_at'))
+  local now = tonumber(ARGV[1])
+  local timeout_ms = tonumber(ARGV[2])
+  if opened_at == nil or (now - opened_at) < timeout_ms then
+    return 0
+  end
+  redis.call('HSET', KEYS[1], 'state', '2')
+end
+
+-- Half-open: at most one in-flight probe across all replicas, leased via SET NX PX.
+local probe_key = KEYS[1] .. ':probe'
+local lease = redis.call('SET', probe_key, '1', 'NX', 'PX', ARGV[3])
+if lease then
+  return 1
+end
+return 0
+`)
+
+var recordFailureScript = redis.NewScript(`
+local failures = redis.call('HINCRBY', KEYS[1], 'failures', 1)
+if tonumber(failures) >= tonumber(ARGV[1]) then
+  redis.call('HSET', KEYS[1], 'state', '1', 'opened_at', ARGV[2])
+end
+return failures
+`)
+
+var recordSuccessScript = redis.NewScript(`
+redis.call('HSET', KEYS[1], 'state', '0', 'failures', '0')
+redis.call('DEL', KEYS[1] .. ':probe')
+return 1
+`)
+
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'timestamp')
+local tokens = tonumber(data[1])
+local timestamp = tonumber(data[2])
+if tokens == nil then
+  tokens = capacity
+  timestamp = now
+end
+
+local elapsed = math.max(0, now - timestamp)
+tokens = math.min(capacity, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= 1 then
+  allowed = 1
+  tokens = tokens - 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tokens, 'timestamp', now)
+redis.call('EXPIRE', KEYS[1], 3600)
+return allowed
+`)
+
+func (b *RedisSharedStateBackend) AllowRequest(ctx context.Context, key string) (bool, error) {
+	now := time.Now().UnixMilli()
+	result, err := allowRequestScript.Run(ctx, b.rdb,
+		[]string{breakerKeyPrefix + key},
+		now, b.openTimeout.Milliseconds(), b.openTimeout.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("shared breaker check failed: %w", err)
+	}
+	return result == 1, nil
+}
+
+func (b *RedisSharedStateBackend) RecordSuccess(ctx context.Context, key string) error {
+	if err := recordSuccessScript.Run(ctx, b.rdb, []string{breakerKeyPrefix + key}).Err(); err != nil {
+		return fmt.Errorf("shared breaker reset failed: %w", err)
+	}
+	return nil
+}
+
+func (b *RedisSharedStateBackend) RecordFailure(ctx context.Context, key string) error {
+	now := time.Now().UnixMilli()
+	if err := recordFailureScript.Run(ctx, b.rdb, []string{breakerKeyPrefix + key}, b.maxFailures, now).Err(); err != nil {
+		return fmt.Errorf("shared breaker failure record failed: %w", err)
+	}
+	return nil
+}
+
+func (b *RedisSharedStateBackend) AllowRate(ctx context.Context, key string, qps float64, burst int) (bool, error) {
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+	result, err := tokenBucketScript.Run(ctx, b.rdb,
+		[]string{rateLimitKeyPrefix + key},
+		burst, qps, now,
+	).Int()
+	if err != nil {
+		return false, fmt.Errorf("shared rate limit check failed: %w", err)
+	}
+	return result == 1, nil
+}
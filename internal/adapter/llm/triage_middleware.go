@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// TriageHandler performs (or continues) triage for threat, the unit a
+// TriageMiddleware wraps - mirroring a gRPC unary handler, but around
+// LLMTriager.Triage instead of an RPC.
+type TriageHandler func(ctx context.Context, threat ThreatContext) (*TriageResult, error)
+
+// TriageMiddleware wraps a TriageHandler with cross-cutting behavior
+// (recovery, logging, timeouts, metrics, ...), in the style of gRPC unary
+// interceptors, so callers can add prompt redaction, request signing, or an
+// audit-log sink without forking LLMTriager.
+type TriageMiddleware func(next TriageHandler) TriageHandler
+
+// Chain composes mw around handler and returns the result. The first
+// middleware given is outermost (it sees the threat first and the result
+// last), matching ResilientClient.Use ordering.
+func Chain(handler TriageHandler, mw ...TriageMiddleware) TriageHandler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler
+}
+
+// Use layers mw around t's current chain (outermost first), so a caller can
+// inject their own middleware - e.g. PII redaction, request signing, or an
+// audit-log sink - on top of the default recovery/metrics/logging chain
+// without forking the triager.
+func (t *LLMTriager) Use(mw ...TriageMiddleware) *LLMTriager {
+	t.chain = Chain(t.chain, mw...)
+	return t
+}
+
+// RecoveryMiddleware recovers from panics anywhere further down the chain
+// (including in other middleware), logs the stack trace, records a "panic"
+// error against llm_api_errors_total, and turns the panic into a plain error
+// instead of crashing the surrounding daemon. It should be installed
+// outermost, matching handler.RecoveryMiddleware's placement for the REST API.
+func RecoveryMiddleware() TriageMiddleware {
+	return func(next TriageHandler) TriageHandler {
+		return func(ctx context.Context, threat ThreatContext) (result *TriageResult, err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("❌ panic recovered in triage pipeline alert_id=%s: %v\n%s",
+						threat.AlertID, rec, debug.Stack())
+					RecordError("panic")
+					result, err = nil, fmt.Errorf("triage pipeline panicked: %v", rec)
+				}
+			}()
+			return next(ctx, threat)
+		}
+	}
+}
+
+// MetricsMiddleware times the wrapped handler against the
+// llm_triage_duration_seconds histogram and, on error, classifies and
+// records it against llm_api_errors_total - the same metrics Triage
+// recorded inline before the chain existed.
+func MetricsMiddleware() TriageMiddleware {
+	return func(next TriageHandler) TriageHandler {
+		return func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+			timer := StartTimer()
+			defer timer.ObserveDuration()
+
+			result, err := next(ctx, threat)
+			if err != nil {
+				RecordError(classifyTriageError(err))
+			}
+			return result, err
+		}
+	}
+}
+
+// classifyTriageError buckets err the way Triage always has, for
+// llm_api_errors_total's "type" label.
+func classifyTriageError(err error) string {
+	switch msg := err.Error(); {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(msg, "circuit breaker"):
+		return "circuit_open"
+	case strings.Contains(msg, "401") || strings.Contains(msg, "403"):
+		return "auth"
+	default:
+		return "parse"
+	}
+}
+
+// TimeoutMiddleware bounds the wrapped handler to timeout, independent of
+// whatever deadline the caller's ctx already carries. timeout <= 0 disables
+// it and the caller's ctx passes through unchanged.
+func TimeoutMiddleware(timeout time.Duration) TriageMiddleware {
+	return func(next TriageHandler) TriageHandler {
+		return func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+			if timeout <= 0 {
+				return next(ctx, threat)
+			}
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, threat)
+		}
+	}
+}
+
+// TriageLoggingMiddleware logs each triage request/response pair, redacting
+// apiKey from any error text (in case an upstream ever echoes it back in a
+// body or header) and IOC values (domains, IPs, hashes under investigation
+// are sensitive forensic data that shouldn't land in log aggregators).
+func TriageLoggingMiddleware(apiKey string) TriageMiddleware {
+	return func(next TriageHandler) TriageHandler {
+		return func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+			iocs := make([]string, 0, len(threat.IOCs))
+			for _, ioc := range threat.IOCs {
+				iocs = append(iocs, redactIOC(ioc))
+			}
+			log.Printf("📨 triage request alert_id=%s threat=%q iocs=%v", threat.AlertID, threat.ThreatName, iocs)
+
+			result, err := next(ctx, threat)
+			if err != nil {
+				log.Printf("📨 triage response alert_id=%s error=%s", threat.AlertID, redactSecret(err.Error(), apiKey))
+				return result, err
+			}
+
+			log.Printf("📨 triage response alert_id=%s severity=%s confidence=%d false_positive=%v",
+				threat.AlertID, result.Severity, result.Confidence, result.FalsePositive)
+			return result, nil
+		}
+	}
+}
+
+// redactSecret masks every occurrence of secret in s. A no-op when secret is
+// empty, so it's safe to call unconditionally even when no API key is set.
+func redactSecret(s, secret string) string {
+	if secret == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, secret, "***redacted***")
+}
+
+// redactIOC returns a safe-to-log stand-in for an indicator value: its type
+// plus a short hash, so the same IOC can be correlated across log lines
+// without exposing the raw domain/IP/hash being investigated.
+func redactIOC(ioc IOCContext) string {
+	sum := sha256.Sum256([]byte(ioc.Value))
+	return fmt.Sprintf("%s:%s", ioc.Type, hex.EncodeToString(sum[:])[:12])
+}
+
+// DefaultTriageChain installs the chain every LLMTriager gets out of the
+// box: recovery outermost (so it also catches panics raised by metrics,
+// logging, or any middleware a caller adds via Use), then metrics, then
+// request/response logging, wrapping handler.
+func DefaultTriageChain(handler TriageHandler, apiKey string) TriageHandler {
+	return Chain(handler, RecoveryMiddleware(), MetricsMiddleware(), TriageLoggingMiddleware(apiKey))
+}
@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChain_OrdersMiddlewareOutermostFirst(t *testing.T) {
+	var order []string
+
+	tag := func(name string) TriageMiddleware {
+		return func(next TriageHandler) TriageHandler {
+			return func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+				order = append(order, name)
+				return next(ctx, threat)
+			}
+		}
+	}
+
+	base := func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+		order = append(order, "base")
+		return &TriageResult{Severity: "info"}, nil
+	}
+
+	handler := Chain(base, tag("outer"), tag("inner"))
+	if _, err := handler(context.Background(), ThreatContext{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"outer", "inner", "base"}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("expected order %v, got %v", want, order)
+			break
+		}
+	}
+}
+
+func TestRecoveryMiddleware_RecoversPanic(t *testing.T) {
+	handler := RecoveryMiddleware()(func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+		panic("boom")
+	})
+
+	result, err := handler(context.Background(), ThreatContext{AlertID: "test-panic"})
+
+	if result != nil {
+		t.Errorf("expected nil result after recovered panic, got %+v", result)
+	}
+	if err == nil {
+		t.Fatal("expected an error after recovered panic")
+	}
+}
+
+func TestRecoveryMiddleware_PassesThroughNormalResult(t *testing.T) {
+	handler := RecoveryMiddleware()(func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+		return &TriageResult{Severity: "high"}, nil
+	})
+
+	result, err := handler(context.Background(), ThreatContext{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Severity != "high" {
+		t.Errorf("expected severity=high, got %s", result.Severity)
+	}
+}
+
+func TestTimeoutMiddleware_CancelsContext(t *testing.T) {
+	var deadlineSet bool
+	handler := TimeoutMiddleware(1)(func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+		_, deadlineSet = ctx.Deadline()
+		return &TriageResult{}, nil
+	})
+
+	if _, err := handler(context.Background(), ThreatContext{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deadlineSet {
+		t.Error("expected TimeoutMiddleware to attach a deadline to the context")
+	}
+}
+
+func TestTimeoutMiddleware_DisabledWhenNonPositive(t *testing.T) {
+	var hadDeadline bool
+	handler := TimeoutMiddleware(0)(func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+		_, hadDeadline = ctx.Deadline()
+		return &TriageResult{}, nil
+	})
+
+	if _, err := handler(context.Background(), ThreatContext{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hadDeadline {
+		t.Error("expected no deadline when timeout <= 0")
+	}
+}
+
+func TestClassifyTriageError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.New("request timeout exceeded"), "timeout"},
+		{errors.New("context deadline exceeded"), "timeout"},
+		{errors.New("circuit breaker open"), "circuit_open"},
+		{errors.New("LLM API error (status 401): unauthorized"), "auth"},
+		{errors.New("failed to decode response: unexpected EOF"), "parse"},
+	}
+
+	for _, tt := range tests {
+		if got := classifyTriageError(tt.err); got != tt.want {
+			t.Errorf("classifyTriageError(%q) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRedactSecret(t *testing.T) {
+	if got := redactSecret("token sk-live-1234 leaked", "sk-live-1234"); got != "token ***redacted*** leaked" {
+		t.Errorf("expected secret to be redacted, got %q", got)
+	}
+	if got := redactSecret("no secret here", ""); got != "no secret here" {
+		t.Errorf("expected no-op when secret is empty, got %q", got)
+	}
+}
+
+func TestRedactIOC_HidesRawValue(t *testing.T) {
+	ioc := IOCContext{Type: "DOMAIN", Value: "malicious-c2.example"}
+	redacted := redactIOC(ioc)
+
+	if redacted == ioc.Value {
+		t.Error("expected redactIOC to not return the raw IOC value")
+	}
+	// Same IOC should always redact to the same value, so log lines can
+	// still be correlated across a request/response pair.
+	if redactIOC(ioc) != redacted {
+		t.Error("expected redactIOC to be deterministic for the same value")
+	}
+}
+
+func TestDefaultTriageChain_WrapsHandler(t *testing.T) {
+	called := false
+	base := func(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+		called = true
+		return &TriageResult{Severity: "medium"}, nil
+	}
+
+	handler := DefaultTriageChain(base, "test-key")
+	result, err := handler(context.Background(), ThreatContext{AlertID: "test-default-chain"})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected base handler to be invoked")
+	}
+	if result.Severity != "medium" {
+		t.Errorf("expected severity=medium, got %s", result.Severity)
+	}
+}
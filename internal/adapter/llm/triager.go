@@ -1,15 +1,15 @@
 package llm
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/hive-corporation/watchtower/internal/core/rules"
+	"go.opentelemetry.io/otel"
 )
 
 // TriageResult contains the LLM's analysis of the threat
@@ -21,6 +21,14 @@ type TriageResult struct {
 	Recommended   []string `json:"recommended"`    // Recommended actions
 	FalsePositive bool     `json:"false_positive"` // Whether it's likely a false positive
 	Confidence    int      `json:"confidence"`     // Confidence in the assessment (0-100)
+
+	// Tags accumulates any rules.ActionTag values from the rules engine
+	// (see rules.go); empty when no rules engine is configured or none
+	// matched.
+	Tags []string `json:"tags,omitempty"`
+	// Queue is the downstream queue a rules.ActionRouteToQueue action
+	// routed this alert to; empty for the default routing.
+	Queue string `json:"queue,omitempty"`
 }
 
 // ThreatContext contains information about the threat to be analyzed
@@ -46,11 +54,27 @@ type IOCContext struct {
 
 // LLMTriager uses LLM to analyze and triage security threats
 type LLMTriager struct {
-	apiURL  string
-	apiKey  string
-	model   string
-	client  *ResilientClient
-	enabled bool
+	apiURL      string
+	apiKey      string
+	model       string
+	client      *ResilientClient
+	enabled     bool
+	ctiProvider CTIProvider
+
+	// rulesEngine runs the operator-authored expr-lang rules (see rules.go)
+	// both pre- and post-LLM. Nil disables rule-based enrichment entirely.
+	rulesEngine *rules.Engine
+
+	// provider is the structured-output backend selected by LLM_PROVIDER
+	// (see provider.go). Triagers built as a struct literal rather than via
+	// NewLLMTriager leave this nil; callProvider falls back to an
+	// OpenAIProvider built from apiURL/apiKey/model in that case.
+	provider LLMProvider
+
+	// chain is the triage pipeline (guardrails, LLM call, parsing) wrapped
+	// in DefaultTriageChain's recovery/metrics/logging middleware. Callers
+	// can layer their own middleware on top via Use.
+	chain TriageHandler
 }
 
 // NewLLMTriager creates a new LLM triager
@@ -62,6 +86,9 @@ func NewLLMTriager() *LLMTriager {
 
 	enabled := os.Getenv("LLM_TRIAGE_ENABLED")
 
+	// Ollama runs locally and needs no API key; every other provider does.
+	requiresAPIKey := strings.ToLower(os.Getenv("LLM_PROVIDER")) != "ollama"
+
 	// Default to LiteLLM proxy (supports multiple providers)
 	apiURL := os.Getenv("LLM_API_URL")
 	if apiURL == "" {
@@ -75,15 +102,35 @@ func NewLLMTriager() *LLMTriager {
 
 	// Create resilient client with circuit breaker and retry logic
 	config := DefaultResilientClientConfig()
-	client := NewResilientClient(30*time.Second, config)
-
-	return &LLMTriager{
-		apiURL:  apiURL,
-		apiKey:  apiKey,
-		model:   model,
-		client:  client,
-		enabled: enabled == "true" && apiKey != "",
+	if backend, err := SharedStateBackendFromEnv(); err != nil {
+		fmt.Printf("⚠️  LLM_RESILIENT_STATE_BACKEND=redis configured but unusable, falling back to in-process breaker: %v\n", err)
+	} else if backend != nil {
+		config.SharedStateBackend = backend
+		config.ProviderName = "llm-api"
+	}
+	if mtlsConfig, err := MTLSConfigFromEnv(); err != nil {
+		fmt.Printf("⚠️  LLM mTLS env vars configured but unusable, falling back to bearer-token auth: %v\n", err)
+	} else if mtlsConfig != nil {
+		config.MTLS = mtlsConfig
+	}
+	client := NewResilientClient(30*time.Second, config).Use(RequestIDMiddleware())
+
+	t := &LLMTriager{
+		apiURL:      apiURL,
+		apiKey:      apiKey,
+		model:       model,
+		client:      client,
+		enabled:     enabled == "true" && (apiKey != "" || !requiresAPIKey),
+		ctiProvider: CTIProviderFromEnv(http.DefaultClient),
+		provider:    ProviderFromEnv(client, apiKey, model),
+	}
+	if engine, err := rules.EngineFromEnv(); err != nil {
+		fmt.Printf("⚠️  RULES_FILE/RULES_DIR configured but unusable, continuing without rule-based enrichment: %v\n", err)
+	} else {
+		t.rulesEngine = engine
 	}
+	t.chain = DefaultTriageChain(t.triage, t.apiKey)
+	return t
 }
 
 // IsEnabled returns whether LLM triaging is enabled
@@ -91,19 +138,53 @@ func (t *LLMTriager) IsEnabled() bool {
 	return t.enabled
 }
 
-// Triage analyzes the threat context and returns a triaging decision
-func (t *LLMTriager) Triage(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
-	// Start timer for metrics
-	timer := StartTimer()
-	defer timer.ObserveDuration()
+// Stats reports the underlying ResilientClient's current rate limit,
+// in-flight count, and circuit state, for a /readyz-style health endpoint.
+func (t *LLMTriager) Stats() ResilientClientStats {
+	return t.client.Stats()
+}
 
+// Triage analyzes the threat context and returns a triaging decision,
+// running it through t.chain (recovery, metrics, and logging middleware by
+// default, plus anything installed via Use) around the actual pipeline in
+// triage.
+func (t *LLMTriager) Triage(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
 	if !t.enabled {
 		return nil, fmt.Errorf("LLM triaging is not enabled")
 	}
+	chain := t.chain
+	if chain == nil {
+		// Triagers built as a struct literal (common in tests) rather than
+		// via NewLLMTriager never had DefaultTriageChain installed.
+		chain = DefaultTriageChain(t.triage, t.apiKey)
+	}
+	return chain(ctx, threat)
+}
+
+// triage is the terminal TriageHandler: pre-LLM guardrails, the LLM call
+// itself, response parsing, and post-LLM guardrails, with config.OnError
+// consulted whenever the LLM call or parsing fails outright.
+func (t *LLMTriager) triage(ctx context.Context, threat ThreatContext) (*TriageResult, error) {
+	// Run the rules engine first - a skip_llm action short-circuits the
+	// whole pipeline the same way a pre-LLM guardrail hit does, and
+	// whatever it decided is re-applied after the LLM call (or the
+	// guardrails' own pre-filter) below.
+	ruleDecision := t.evaluateRules(threat)
+	if ruleDecision.SkipLLM {
+		result := applyRuleDecision(minimalTriageResult(threat, "skipped by rule"), ruleDecision)
+		RecordTriageRequest("skipped", "rule")
+		RecordResult(result)
+		if result.FalsePositive {
+			RecordFalsePositive()
+		}
+		return result, nil
+	}
 
 	// Apply pre-LLM guardrails (rule-based filters)
 	config := DefaultGuardrailConfig()
-	if preResult, shouldSkip := ApplyPreLLMGuardrails(threat, config); shouldSkip {
+	config.CTIProvider = t.ctiProvider
+	if preResult, shouldSkip := ApplyPreLLMGuardrails(ctx, threat, config); shouldSkip {
+		preResult = applyRuleDecision(preResult, ruleDecision)
 		// Record that we skipped the LLM call due to pre-filter
 		RecordTriageRequest("skipped", "pre_filter")
 		RecordResult(preResult)
@@ -116,33 +197,23 @@ func (t *LLMTriager) Triage(ctx context.Context, threat ThreatContext) (*TriageR
 	// Build the prompt
 	prompt := t.buildPrompt(threat)
 
-	// Call LLM API
-	response, err := t.callLLM(ctx, prompt)
+	// Call the configured LLM provider, constrained to TriageResult's shape
+	result, err := t.callProvider(ctx, triageSystemPrompt, prompt)
 	if err != nil {
 		RecordTriageRequest("error", "llm")
-		// Try to classify the error type
-		if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded") {
-			RecordError("timeout")
-		} else if strings.Contains(err.Error(), "circuit breaker") {
-			RecordError("circuit_open")
-		} else if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "403") {
-			RecordError("auth")
-		} else {
-			RecordError("parse")
+		if onErrorResult, handled := DefaultOnError(ctx, threat, config, err.Error()); handled {
+			return onErrorResult, nil
 		}
+		// MetricsMiddleware classifies and records this against
+		// llm_api_errors_total once it sees the returned error.
 		return nil, fmt.Errorf("failed to call LLM: %w", err)
 	}
 
-	// Parse the response
-	result, err := t.parseResponse(response)
-	if err != nil {
-		RecordTriageRequest("error", "parse")
-		RecordError("parse")
-		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
-	}
-
-	// Apply post-LLM guardrails (validation and adjustment)
-	result = ApplyPostLLMGuardrails(result, threat, config)
+	// Apply post-LLM guardrails (validation and adjustment), then re-apply
+	// the rules engine's decision so rule actions always win over whatever
+	// the LLM (and guardrails) concluded.
+	result = ApplyPostLLMGuardrails(ctx, result, threat, config)
+	result = applyRuleDecision(result, ruleDecision)
 
 	// Record successful triage
 	RecordTriageRequest("success", "llm")
@@ -228,91 +299,24 @@ func (t *LLMTriager) buildPrompt(threat ThreatContext) string {
 	return sb.String()
 }
 
-func (t *LLMTriager) callLLM(ctx context.Context, prompt string) (string, error) {
-	// Build request body
-	requestBody := map[string]interface{}{
-		"model": t.model,
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": "You are an expert cybersecurity analyst. Analyze threats and provide structured assessments in JSON format.",
-			},
-			{
-				"role":    "user",
-				"content": prompt,
-			},
-		},
-		"temperature": 0.3, // Lower temperature for more consistent analysis
-		"max_tokens":  1000,
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", t.apiURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", t.apiKey))
-
-	// Send request
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("LLM API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	var response struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
+// triageSystemPrompt is sent as the system message/prompt to whichever
+// LLMProvider is configured.
+const triageSystemPrompt = "You are an expert cybersecurity analyst. Analyze threats and provide structured assessments."
 
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no choices in LLM response")
-	}
+// callProvider runs systemPrompt/userPrompt through t.provider, constrained
+// to triageResultJSONSchema. Triagers built as a struct literal (common in
+// tests) rather than via NewLLMTriager never had a provider installed, so
+// it falls back to treating apiURL as an OpenAI-compatible endpoint.
+func (t *LLMTriager) callProvider(ctx context.Context, systemPrompt, userPrompt string) (*TriageResult, error) {
+	ctx, span := otel.Tracer("watchtower/llm").Start(ctx, "LLMTriager.callProvider")
+	defer span.End()
 
-	return response.Choices[0].Message.Content, nil
-}
+	start := time.Now()
+	defer func() { RecordCallDuration(time.Since(start)) }()
 
-func (t *LLMTriager) parseResponse(response string) (*TriageResult, error) {
-	// Extract JSON from markdown code blocks if present
-	jsonStr := response
-	if idx := strings.Index(response, "```json"); idx != -1 {
-		jsonStr = response[idx+7:]
-		if endIdx := strings.Index(jsonStr, "```"); endIdx != -1 {
-			jsonStr = jsonStr[:endIdx]
-		}
-	} else if idx := strings.Index(response, "```"); idx != -1 {
-		jsonStr = response[idx+3:]
-		if endIdx := strings.Index(jsonStr, "```"); endIdx != -1 {
-			jsonStr = jsonStr[:endIdx]
-		}
+	provider := t.provider
+	if provider == nil {
+		provider = NewOpenAIProvider(t.client, t.apiURL, t.apiKey, t.model)
 	}
-
-	jsonStr = strings.TrimSpace(jsonStr)
-
-	var result TriageResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w (response: %s)", err, jsonStr)
-	}
-
-	return &result, nil
+	return provider.Complete(ctx, systemPrompt, userPrompt, triageResultJSONSchema)
 }
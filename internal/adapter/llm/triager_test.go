@@ -66,73 +66,6 @@ func TestBuildPrompt(t *testing.T) {
 	}
 }
 
-func TestParseResponse(t *testing.T) {
-	triager := &LLMTriager{}
-
-	tests := []struct {
-		name     string
-		response string
-		wantErr  bool
-	}{
-		{
-			name: "Valid JSON in markdown",
-			response: "```json\n" +
-				`{"severity":"high","priority":2,"summary":"Test","analysis":"Test analysis","recommended":["Action 1"],"false_positive":false,"confidence":85}` +
-				"\n```",
-			wantErr: false,
-		},
-		{
-			name:     "Valid JSON without markdown",
-			response: `{"severity":"medium","priority":3,"summary":"Test","analysis":"Test","recommended":[],"false_positive":false,"confidence":70}`,
-			wantErr:  false,
-		},
-		{
-			name:     "Invalid JSON",
-			response: "not a valid json",
-			wantErr:  true,
-		},
-		{
-			name: "JSON with extra text",
-			response: "Here is my analysis:\n```json\n" +
-				`{"severity":"low","priority":4,"summary":"Test","analysis":"Test","recommended":[],"false_positive":true,"confidence":90}` +
-				"\n```\nHope this helps!",
-			wantErr: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := triager.parseResponse(tt.response)
-
-			if tt.wantErr {
-				if err == nil {
-					t.Error("Expected error but got none")
-				}
-				return
-			}
-
-			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
-				return
-			}
-
-			if result == nil {
-				t.Error("Expected non-nil result")
-				return
-			}
-
-			// Verify result has required fields
-			if result.Severity == "" {
-				t.Error("Expected severity to be set")
-			}
-
-			if result.Summary == "" {
-				t.Error("Expected summary to be set")
-			}
-		})
-	}
-}
-
 func TestTriageWithMockLLM(t *testing.T) {
 	// Create mock LLM server
 	mockResponse := map[string]interface{}{
@@ -306,70 +239,6 @@ func TestTriageDisabled(t *testing.T) {
 	}
 }
 
-func TestCallLLMTimeout(t *testing.T) {
-	// Create slow server that will timeout
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(2 * time.Second)
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
-
-	config := ResilientClientConfig{
-		EnableCircuitBreaker: false,
-		MaxRetries:           0,
-		InitialInterval:      100 * time.Millisecond,
-		MaxInterval:          1 * time.Second,
-	}
-	triager := &LLMTriager{
-		apiURL:  server.URL,
-		apiKey:  "test-key",
-		model:   "gpt-4o-mini",
-		client:  NewResilientClient(100*time.Millisecond, config), // Short timeout
-		enabled: true,
-	}
-
-	ctx := context.Background()
-	_, err := triager.callLLM(ctx, "test prompt")
-
-	if err == nil {
-		t.Error("Expected timeout error")
-	}
-}
-
-func TestCallLLMErrorResponse(t *testing.T) {
-	// Create server that returns error
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Internal server error"))
-	}))
-	defer server.Close()
-
-	config := ResilientClientConfig{
-		EnableCircuitBreaker: false,
-		MaxRetries:           0,
-		InitialInterval:      100 * time.Millisecond,
-		MaxInterval:          1 * time.Second,
-	}
-	triager := &LLMTriager{
-		apiURL:  server.URL,
-		apiKey:  "test-key",
-		model:   "gpt-4o-mini",
-		client:  NewResilientClient(5*time.Second, config),
-		enabled: true,
-	}
-
-	ctx := context.Background()
-	_, err := triager.callLLM(ctx, "test prompt")
-
-	if err == nil {
-		t.Error("Expected error for 500 status code")
-	}
-
-	if !strings.Contains(err.Error(), "500") {
-		t.Errorf("Expected error message to contain status code, got: %v", err)
-	}
-}
-
 func TestNewLLMTriager(t *testing.T) {
 	// Save original env
 	originalEnabled := ""
@@ -440,14 +309,3 @@ func BenchmarkBuildPrompt(b *testing.B) {
 		triager.buildPrompt(threat)
 	}
 }
-
-func BenchmarkParseResponse(b *testing.B) {
-	triager := &LLMTriager{}
-
-	response := `{"severity":"high","priority":2,"summary":"Test","analysis":"Test analysis","recommended":["Action 1","Action 2"],"false_positive":false,"confidence":85}`
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		triager.parseResponse(response)
-	}
-}
@@ -0,0 +1,52 @@
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// CargoLockParser parses Cargo.lock, which uses the same "[[package]]" TOML
+// table shape as poetry.lock (name + version scalars), so it shares the same
+// line-scan approach rather than a general TOML library.
+type CargoLockParser struct{}
+
+func (CargoLockParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	var name, version string
+	inPackage := false
+
+	flush := func() {
+		if inPackage && name != "" {
+			deps = append(deps, Dependency{Ecosystem: "cargo", Name: name, Version: version})
+		}
+		name, version = "", ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "[[package]]" {
+			flush()
+			inPackage = true
+			continue
+		}
+		if strings.HasPrefix(line, "[") && line != "[[package]]" {
+			inPackage = false
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+
+		if v, ok := tomlStringValue(line, "name"); ok {
+			name = v
+		} else if v, ok := tomlStringValue(line, "version"); ok {
+			version = v
+		}
+	}
+	flush()
+
+	return deps, scanner.Err()
+}
@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// GemfileLockParser parses Gemfile.lock's "specs:" section. Locked gems are
+// indented exactly 4 spaces as "name (version)"; their own dependencies are
+// listed beneath them indented 6 spaces and are skipped, since those same
+// gems also appear as their own top-level 4-space entry elsewhere in the file.
+type GemfileLockParser struct{}
+
+func (GemfileLockParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	inSpecs := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "specs:" {
+			inSpecs = true
+			continue
+		}
+		if !inSpecs {
+			continue
+		}
+		if trimmed == "" {
+			inSpecs = false // a blank line ends the GEM block
+			continue
+		}
+		if !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			continue // not a 4-space top-level gem line
+		}
+
+		name, version, ok := splitGemSpec(trimmed)
+		if !ok {
+			continue
+		}
+		deps = append(deps, Dependency{Ecosystem: "gem", Name: name, Version: version})
+	}
+
+	return deps, scanner.Err()
+}
+
+// splitGemSpec parses "name (version)" into its parts.
+func splitGemSpec(spec string) (name, version string, ok bool) {
+	open := strings.Index(spec, " (")
+	if open == -1 || !strings.HasSuffix(spec, ")") {
+		return "", "", false
+	}
+	return spec[:open], spec[open+2 : len(spec)-1], true
+}
@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// GoModParser parses go.mod files, preserving the scanner's original
+// "require"/"module"/"go" line-munging and /v2-suffix trimming.
+type GoModParser struct{}
+
+func (GoModParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+
+		pkgName := parts[0]
+		version := ""
+		if pkgName == "require" || pkgName == "module" || pkgName == "go" || pkgName == "//" {
+			if len(parts) > 2 && parts[0] == "require" {
+				// Single-line form: "require github.com/foo/bar v1.2.3"
+				pkgName = parts[1]
+				version = parts[2]
+			} else {
+				continue
+			}
+		} else if len(parts) > 1 {
+			// Inside a "require (...)" block: "github.com/foo/bar v1.2.3"
+			version = parts[1]
+		}
+
+		pkgName = strings.TrimSuffix(pkgName, "/v2")
+
+		deps = append(deps, Dependency{Ecosystem: "go", Name: pkgName, Version: version})
+	}
+
+	return deps, scanner.Err()
+}
+
+// GoSumParser parses go.sum, which lists every module version actually
+// resolved into the build (including indirect and test-only dependencies
+// go.mod itself doesn't mention), deduping the "/go.mod hash" companion line
+// go.sum emits alongside each module's content hash.
+type GoSumParser struct{}
+
+func (GoSumParser) Parse(r io.Reader) ([]Dependency, error) {
+	seen := make(map[string]bool)
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.Fields(strings.TrimSpace(scanner.Text()))
+		if len(parts) < 2 {
+			continue
+		}
+
+		module := strings.TrimSuffix(parts[0], "/v2")
+		version := strings.TrimSuffix(parts[1], "/go.mod")
+
+		key := module + "@" + version
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		deps = append(deps, Dependency{Ecosystem: "go", Name: module, Version: version})
+	}
+
+	return deps, scanner.Err()
+}
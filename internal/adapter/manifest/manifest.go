@@ -0,0 +1,101 @@
+// Package manifest parses dependency manifests/lockfiles from the
+// ecosystems the scanner CLI supports (Go modules, npm, PyPI, crates.io,
+// RubyGems, Maven/Gradle) into a common Dependency shape, so the CLI can
+// check every ecosystem's packages against the IOC database without
+// hard-coding one parsing scheme per file format.
+package manifest
+
+import (
+	"fmt"
+	"io"
+)
+
+// Dependency is one resolved package reference extracted from a manifest or
+// lockfile, tagged with the ecosystem it came from so the same package name
+// in two ecosystems (e.g. "requests" on PyPI vs npm) isn't treated as one IOC.
+type Dependency struct {
+	Ecosystem string // "go", "npm", "pypi", "cargo", "gem", "maven"
+	Name      string
+	Version   string // empty when the manifest doesn't pin a version (e.g. requirements.txt without ==)
+}
+
+// ecosystemPrefixes namespaces PackageRef values by ecosystem so collisions
+// across ecosystems (two different "requests" packages) don't collide in the
+// IOC database, which keys purely on the value string.
+var ecosystemPrefixes = map[string]string{
+	"go":    "go",
+	"npm":   "npm",
+	"pypi":  "pypi",
+	"cargo": "cargo",
+	"gem":   "gem",
+	"maven": "maven",
+}
+
+// PackageRef renders the dependency as the "ecosystem:package@version" value
+// sent to the IOC database, so SearchIOC's package@version splitting
+// (parsePackageVersion) is exercised end to end. The version is omitted when
+// unknown, leaving a bare "ecosystem:package" reference.
+func (d Dependency) PackageRef() string {
+	prefix := ecosystemPrefixes[d.Ecosystem]
+	if prefix == "" {
+		prefix = d.Ecosystem
+	}
+
+	ref := fmt.Sprintf("%s:%s", prefix, d.Name)
+	if d.Version != "" {
+		ref = fmt.Sprintf("%s@%s", ref, d.Version)
+	}
+	return ref
+}
+
+// Parser extracts dependencies from one manifest/lockfile format.
+type Parser interface {
+	Parse(r io.Reader) ([]Dependency, error)
+}
+
+// detector pairs a Parser with the filenames it claims to handle.
+type detector struct {
+	filenames []string
+	parser    Parser
+}
+
+// detectors is the auto-detect table consulted by Detect, in the order new
+// formats are added below.
+var detectors = []detector{
+	{[]string{"go.mod"}, GoModParser{}},
+	{[]string{"go.sum"}, GoSumParser{}},
+	{[]string{"package-lock.json"}, PackageLockParser{}},
+	{[]string{"pnpm-lock.yaml"}, PnpmLockParser{}},
+	{[]string{"requirements.txt"}, RequirementsParser{}},
+	{[]string{"poetry.lock"}, PoetryLockParser{}},
+	{[]string{"Cargo.lock"}, CargoLockParser{}},
+	{[]string{"Gemfile.lock"}, GemfileLockParser{}},
+	{[]string{"pom.xml"}, PomParser{}},
+	{[]string{"build.gradle", "build.gradle.kts"}, GradleParser{}},
+}
+
+// Detect returns the Parser registered for filename (matched against the
+// base name, not the full path), or false if no parser recognizes it.
+func Detect(filename string) (Parser, bool) {
+	base := baseName(filename)
+	for _, d := range detectors {
+		for _, name := range d.filenames {
+			if base == name {
+				return d.parser, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// baseName mirrors filepath.Base without importing path/filepath here, to
+// keep this file's dependency footprint limited to what Dependency/Parser
+// themselves need.
+func baseName(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' || path[i] == '\\' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// PomParser parses a Maven pom.xml's <dependencies> block. It only reads
+// direct dependencies, not the full effective POM (parent/BOM-managed
+// versions aren't resolved), which matches what's literally committed to the
+// repository rather than requiring a Maven install to compute.
+type PomParser struct{}
+
+type pomProject struct {
+	Dependencies struct {
+		Dependency []pomDependency `xml:"dependency"`
+	} `xml:"dependencies"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+func (PomParser) Parse(r io.Reader) ([]Dependency, error) {
+	var project pomProject
+	if err := xml.NewDecoder(r).Decode(&project); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(project.Dependencies.Dependency))
+	for _, d := range project.Dependencies.Dependency {
+		if d.GroupID == "" || d.ArtifactID == "" {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Ecosystem: "maven",
+			Name:      d.GroupID + ":" + d.ArtifactID,
+			Version:   d.Version,
+		})
+	}
+	return deps, nil
+}
+
+// gradleDependencyPattern matches a Gradle dependency declaration such as
+// `implementation 'com.squareup.okhttp3:okhttp:4.11.0'` or the Kotlin DSL's
+// double-quoted equivalent, across the configurations Gradle projects
+// commonly use.
+var gradleDependencyPattern = regexp.MustCompile(
+	`(?:implementation|api|compile|testImplementation|androidTestImplementation|runtimeOnly|compileOnly)\s*[\(]?\s*['"]([^:'"]+):([^:'"]+):([^'"]+)['"]`,
+)
+
+// GradleParser extracts dependencies from build.gradle/build.gradle.kts via
+// regex rather than a real Groovy/Kotlin parser, since Gradle files are an
+// executable DSL with no fixed grammar a generic parser could rely on; this
+// covers the overwhelmingly common `config 'group:artifact:version'` form.
+type GradleParser struct{}
+
+func (GradleParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "//") {
+			continue
+		}
+
+		match := gradleDependencyPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Ecosystem: "maven",
+			Name:      match[1] + ":" + match[2],
+			Version:   match[3],
+		})
+	}
+
+	return deps, scanner.Err()
+}
@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// PackageLockParser parses npm's package-lock.json. It understands the
+// lockfile v2/v3 "packages" map (keyed by node_modules path, e.g.
+// "node_modules/lodash" or the scoped "node_modules/@babel/core"), falling
+// back to the older v1 top-level "dependencies" map when "packages" is
+// absent.
+type PackageLockParser struct{}
+
+type packageLockFile struct {
+	Packages     map[string]packageLockEntry `json:"packages"`
+	Dependencies map[string]packageLockEntry `json:"dependencies"`
+}
+
+type packageLockEntry struct {
+	Version string `json:"version"`
+}
+
+func (PackageLockParser) Parse(r io.Reader) ([]Dependency, error) {
+	var file packageLockFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+
+	if len(file.Packages) > 0 {
+		for path, entry := range file.Packages {
+			if path == "" || entry.Version == "" {
+				continue // the root project entry has no node_modules path and no version
+			}
+			name := strings.TrimPrefix(path, "node_modules/")
+			if idx := strings.LastIndex(name, "node_modules/"); idx != -1 {
+				name = name[idx+len("node_modules/"):] // nested transitive copy; keep just the package name
+			}
+			deps = append(deps, Dependency{Ecosystem: "npm", Name: name, Version: entry.Version})
+		}
+		return deps, nil
+	}
+
+	for name, entry := range file.Dependencies {
+		deps = append(deps, Dependency{Ecosystem: "npm", Name: name, Version: entry.Version})
+	}
+	return deps, nil
+}
+
+// PnpmLockParser parses pnpm-lock.yaml's "packages:" section by hand rather
+// than pulling in a full YAML library: pnpm's package keys are a fixed,
+// line-oriented shape ("  /name@version:" or, on newer lockfile versions,
+// "  name@version:" without the leading slash), which a small line scanner
+// handles without the complexity of a general YAML parser.
+type PnpmLockParser struct{}
+
+func (PnpmLockParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	inPackages := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimRight(line, " \t")
+
+		if trimmed == "packages:" {
+			inPackages = true
+			continue
+		}
+		if !inPackages {
+			continue
+		}
+
+		// A line back at column 0 ends the packages section.
+		if trimmed != "" && trimmed[0] != ' ' {
+			break
+		}
+
+		key := strings.TrimSpace(trimmed)
+		key = strings.TrimPrefix(key, "/")
+		if !strings.HasSuffix(key, ":") {
+			continue // a nested field of the current package entry (resolution:, dependencies:, ...), not a package key
+		}
+		key = strings.TrimSuffix(key, ":")
+
+		name, version, ok := splitPnpmKey(key)
+		if !ok {
+			continue
+		}
+		deps = append(deps, Dependency{Ecosystem: "npm", Name: name, Version: version})
+	}
+
+	return deps, scanner.Err()
+}
+
+// splitPnpmKey splits a pnpm package key ("lodash@4.17.21" or the scoped
+// "@babel/core@7.21.0") on its last '@', which separates the version from a
+// package name that may itself contain '@' as part of a scope.
+func splitPnpmKey(key string) (name, version string, ok bool) {
+	lastAt := strings.LastIndex(key, "@")
+	if lastAt <= 0 {
+		return "", "", false
+	}
+	return key[:lastAt], key[lastAt+1:], true
+}
@@ -0,0 +1,113 @@
+package manifest
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// RequirementsParser parses requirements.txt. Only the "==" pin is treated
+// as a resolved version; ranges (">=", "~=", ...) and bare names are emitted
+// with an empty Version, since requirements.txt alone doesn't say which
+// version actually got installed.
+type RequirementsParser struct{}
+
+func (RequirementsParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue // blank, comment, or an option line like "-r other.txt" / "--index-url ..."
+		}
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = strings.TrimSpace(line[:idx]) // strip an inline comment
+		}
+		if idx := strings.IndexAny(line, ";["); idx != -1 {
+			line = strings.TrimSpace(line[:idx]) // strip environment markers / extras, e.g. "foo[extra]; python_version<'3.8'"
+		}
+
+		name, version := splitPinnedRequirement(line)
+		if name == "" {
+			continue
+		}
+		deps = append(deps, Dependency{Ecosystem: "pypi", Name: name, Version: version})
+	}
+
+	return deps, scanner.Err()
+}
+
+// splitPinnedRequirement splits "name==version" into its parts, returning an
+// empty version for any other operator (>=, ~=, !=, ...) or a bare name.
+func splitPinnedRequirement(req string) (name, version string) {
+	if idx := strings.Index(req, "=="); idx != -1 {
+		return strings.TrimSpace(req[:idx]), strings.TrimSpace(req[idx+2:])
+	}
+	for _, op := range []string{">=", "<=", "~=", "!=", ">", "<"} {
+		if idx := strings.Index(req, op); idx != -1 {
+			return strings.TrimSpace(req[:idx]), ""
+		}
+	}
+	return strings.TrimSpace(req), ""
+}
+
+// PoetryLockParser parses poetry.lock's "[[package]]" TOML tables by hand:
+// each table is just two scalar fields we care about (name, version), which
+// is simple enough to line-scan without a general TOML library.
+type PoetryLockParser struct{}
+
+func (PoetryLockParser) Parse(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	var name, version string
+	inPackage := false
+
+	flush := func() {
+		if inPackage && name != "" {
+			deps = append(deps, Dependency{Ecosystem: "pypi", Name: name, Version: version})
+		}
+		name, version = "", ""
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "[[package]]" {
+			flush()
+			inPackage = true
+			continue
+		}
+		if strings.HasPrefix(line, "[") && line != "[[package]]" {
+			// Entering a different table ([package.dependencies], [[package.source]], ...)
+			inPackage = false
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+
+		if v, ok := tomlStringValue(line, "name"); ok {
+			name = v
+		} else if v, ok := tomlStringValue(line, "version"); ok {
+			version = v
+		}
+	}
+	flush()
+
+	return deps, scanner.Err()
+}
+
+// tomlStringValue matches a simple `key = "value"` TOML line and returns its
+// unquoted value.
+func tomlStringValue(line, key string) (string, bool) {
+	prefix := key + " = \""
+	if !strings.HasPrefix(line, prefix) {
+		return "", false
+	}
+	rest := line[len(prefix):]
+	if idx := strings.Index(rest, "\""); idx != -1 {
+		return rest[:idx], true
+	}
+	return "", false
+}
@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// sarifSchemaURI and sarifVersion identify the SARIF 2.1.0 static analysis
+// results format GitHub code scanning accepts.
+const (
+	sarifSchemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+	sarifVersion   = "2.1.0"
+)
+
+// sarifLog is the top-level SARIF document: one "run" for the watchtower
+// scanner tool, carrying one result per flagged dependency.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string         `json:"id"`
+	ShortDescription sarifText      `json:"shortDescription"`
+	Properties       sarifRuleProps `json:"properties,omitempty"`
+}
+
+type sarifRuleProps struct {
+	SecuritySeverity string `json:"security-severity,omitempty"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Finding is one flagged dependency, ready to render as a SARIF result.
+type Finding struct {
+	Dependency   Dependency
+	ThreatType   string
+	ManifestFile string
+}
+
+// WriteSARIF renders findings as a SARIF 2.1.0 log so the output can be
+// uploaded directly as GitHub code-scanning results (e.g. via
+// github/codeql-action/upload-sarif).
+func WriteSARIF(w io.Writer, findings []Finding) error {
+	rulesSeen := make(map[string]bool)
+	log := sarifLog{
+		Schema:  sarifSchemaURI,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "watchtower-scanner",
+					InformationURI: "https://github.com/hive-corporation/watchtower",
+				},
+			},
+			Results: make([]sarifResult, 0, len(findings)),
+		}},
+	}
+
+	run := &log.Runs[0]
+	for _, f := range findings {
+		ruleID := f.ThreatType
+		if ruleID == "" {
+			ruleID = "malicious-dependency"
+		}
+		if !rulesSeen[ruleID] {
+			rulesSeen[ruleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               ruleID,
+				ShortDescription: sarifText{Text: "Known-malicious dependency: " + ruleID},
+				Properties:       sarifRuleProps{SecuritySeverity: "9.0"},
+			})
+		}
+
+		uri := f.ManifestFile
+		if uri == "" {
+			uri = "go.mod"
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID: ruleID,
+			Level:  "error",
+			Message: sarifText{
+				Text: f.Dependency.PackageRef() + " is a known-malicious dependency",
+			},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: uri},
+				},
+			}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,67 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ScanFile detects and parses a single manifest/lockfile, returning an error
+// if its filename isn't recognized by any registered Parser.
+func ScanFile(path string) ([]Dependency, error) {
+	parser, ok := Detect(path)
+	if !ok {
+		return nil, fmt.Errorf("no parser registered for %s", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	deps, err := parser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return deps, nil
+}
+
+// ScanDir walks root recursively, parsing every manifest/lockfile it
+// recognizes by filename and concatenating the results. It skips
+// node_modules, vendor and .git directories, which routinely contain nested
+// copies of the very lockfiles being scanned for and would otherwise blow up
+// scan time without adding any dependencies the top-level lockfile doesn't
+// already cover.
+func ScanDir(root string) ([]Dependency, error) {
+	var all []Dependency
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case "node_modules", "vendor", ".git":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if _, ok := Detect(path); !ok {
+			return nil
+		}
+
+		deps, err := ScanFile(path)
+		if err != nil {
+			return err
+		}
+		all = append(all, deps...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Deduper wraps a Notifier and suppresses an alert whose fingerprint (alert
+// ID / IOC value, IOC values, and severity) was already seen within window,
+// so a flapping detection firing every few seconds doesn't spam the
+// configured channels with duplicates of the same event.
+type Deduper struct {
+	target Notifier
+	store  DedupeStore
+	window time.Duration
+}
+
+// NewDeduper wraps target, suppressing any alert whose fingerprint repeats
+// within window. store persists the seen fingerprints - pass
+// NewInMemoryDedupeStore() for in-process-only dedup, or a BoltDedupeStore
+// so the window survives a restart.
+func NewDeduper(target Notifier, store DedupeStore, window time.Duration) *Deduper {
+	return &Deduper{target: target, store: store, window: window}
+}
+
+func (d *Deduper) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	if d.suppress(fingerprint(alert.AlertID, iocValues(enriched), "")) {
+		return nil
+	}
+	return d.target.NotifySentinelOneDetection(alert, enriched)
+}
+
+func (d *Deduper) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	if d.suppress(fingerprint(ioc.Value, []string{ioc.Type}, "")) {
+		return nil
+	}
+	return d.target.NotifyHighConfidenceIOC(ioc)
+}
+
+func (d *Deduper) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	if d.suppress(fingerprint(pkg.PackageName, []string{pkg.Version}, "")) {
+		return nil
+	}
+	return d.target.NotifySupplyChainThreat(pkg)
+}
+
+func (d *Deduper) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triageResult *TriageResult) error {
+	severity := ""
+	if triageResult != nil {
+		severity = triageResult.Severity
+	}
+	if d.suppress(fingerprint(alert.AlertID, iocValues(enriched), severity)) {
+		return nil
+	}
+	return d.target.NotifySentinelOneDetectionWithTriage(alert, enriched, triageResult)
+}
+
+// suppress reports whether fp was already recorded within window, and
+// refreshes the stored timestamp to now regardless - so a detection
+// flapping continuously stays suppressed instead of slipping back through
+// right as the window rolls over on its last fire.
+func (d *Deduper) suppress(fp string) bool {
+	now := time.Now()
+
+	lastSeen, ok, err := d.store.LastSeen(fp)
+	if err != nil {
+		// Fail open: a broken dedupe store should never cause a real alert
+		// to go missing.
+		log.Printf("⚠️  dedupe store lookup failed, sending anyway: %v", err)
+		return false
+	}
+	duplicate := ok && now.Sub(lastSeen) < d.window
+
+	if err := d.store.Record(fp, now); err != nil {
+		log.Printf("⚠️  dedupe store record failed: %v", err)
+	}
+
+	if duplicate {
+		RecordSuppressed()
+	}
+	return duplicate
+}
+
+// fingerprint hashes id (an alert ID or IOC value), the IOC values involved,
+// and severity into the key Deduper tracks duplicates by.
+func fingerprint(id string, values []string, severity string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%s|%s", id, strings.Join(values, ","), severity)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func iocValues(enriched []EnrichedIndicator) []string {
+	values := make([]string, len(enriched))
+	for i, ind := range enriched {
+		values[i] = ind.Value
+	}
+	return values
+}
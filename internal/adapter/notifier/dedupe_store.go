@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// DedupeStore records when a fingerprint was last seen, so Deduper can
+// suppress repeat alerts within a window even across process restarts.
+type DedupeStore interface {
+	// LastSeen returns when fingerprint was last recorded, and whether it
+	// had been recorded at all.
+	LastSeen(fingerprint string) (seenAt time.Time, ok bool, err error)
+	// Record persists fingerprint as seen at seenAt, replacing whatever
+	// timestamp (if any) was recorded for it before.
+	Record(fingerprint string, seenAt time.Time) error
+}
+
+// InMemoryDedupeStore is the default DedupeStore: seen fingerprints live
+// only in process memory, so a restart re-alerts on anything still within
+// its window. Use NewBoltDedupeStore instead when restarts shouldn't reset
+// the dedupe window.
+type InMemoryDedupeStore struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewInMemoryDedupeStore returns an empty InMemoryDedupeStore.
+func NewInMemoryDedupeStore() *InMemoryDedupeStore {
+	return &InMemoryDedupeStore{seen: make(map[string]time.Time)}
+}
+
+func (s *InMemoryDedupeStore) LastSeen(fingerprint string) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seenAt, ok := s.seen[fingerprint]
+	return seenAt, ok, nil
+}
+
+func (s *InMemoryDedupeStore) Record(fingerprint string, seenAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[fingerprint] = seenAt
+	return nil
+}
+
+// dedupeBucketName is the single BoltDB bucket BoltDedupeStore keeps all
+// fingerprint -> last-seen entries in.
+var dedupeBucketName = []byte("notifier_dedupe")
+
+// BoltDedupeStore persists seen fingerprints to a BoltDB file, so the
+// dedupe window survives a watchtower restart instead of re-alerting on
+// everything that was in flight when it last stopped.
+type BoltDedupeStore struct {
+	db *bbolt.DB
+}
+
+// NewBoltDedupeStore opens (creating if necessary) a BoltDB file at path
+// for dedupe state.
+func NewBoltDedupeStore(path string) (*BoltDedupeStore, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dedupe store %s: %w", path, err)
+	}
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(dedupeBucketName)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize dedupe bucket: %w", err)
+	}
+
+	return &BoltDedupeStore{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *BoltDedupeStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltDedupeStore) LastSeen(fingerprint string) (time.Time, bool, error) {
+	var seenAt time.Time
+	var ok bool
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(dedupeBucketName).Get([]byte(fingerprint))
+		if v == nil {
+			return nil
+		}
+		ok = true
+		seenAt = time.Unix(0, int64(binary.BigEndian.Uint64(v)))
+		return nil
+	})
+	return seenAt, ok, err
+}
+
+func (s *BoltDedupeStore) Record(fingerprint string, seenAt time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, uint64(seenAt.UnixNano()))
+		return tx.Bucket(dedupeBucketName).Put([]byte(fingerprint), buf)
+	})
+}
@@ -0,0 +1,165 @@
+package notifier
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// digestSeverities are the triage severities DigestNotifier buffers into a
+// consolidated message instead of sending immediately. Anything else
+// (high, critical) always fires right away.
+var digestSeverities = map[string]bool{"low": true, "medium": true}
+
+// DigestEntry summarizes every buffered alert sharing an endpoint and
+// threat name, for the table DigestTarget.NotifyDigest renders.
+type DigestEntry struct {
+	Endpoint string
+	Threat   string
+	Severity string
+	Count    int
+}
+
+// DigestTarget is an optional extension of Notifier a channel can implement
+// to render a consolidated digest message. DigestNotifier type-asserts for
+// this interface, falling back to resending each buffered group's first
+// alert individually when target doesn't implement it. SlackNotifier
+// implements it.
+type DigestTarget interface {
+	Notifier
+	NotifyDigest(entries []DigestEntry) error
+}
+
+// digestBucket is what DigestNotifier buffers per endpoint+threat group:
+// the summary DigestEntry plus the first alert seen, kept around only so
+// the no-DigestTarget fallback path has something to resend.
+type digestBucket struct {
+	entry    DigestEntry
+	alert    SentinelOneAlert
+	enriched []EnrichedIndicator
+	triage   *TriageResult
+}
+
+// DigestNotifier wraps a Notifier and, for low/medium-severity triaged
+// SentinelOne alerts, buffers them for window and flushes a single
+// consolidated digest instead of one message per event. High/critical
+// alerts - and every other Notify* call, none of which carry a severity -
+// pass through to target immediately. Call Start once to begin the flush
+// loop.
+type DigestNotifier struct {
+	target Notifier
+	window time.Duration
+
+	mu     sync.Mutex
+	buffer map[string]*digestBucket
+	stopCh chan struct{}
+}
+
+// NewDigestNotifier returns a DigestNotifier that buffers low/medium
+// triaged alerts for window before flushing into a single digest via
+// target (or target.NotifyDigest, if target implements DigestTarget).
+func NewDigestNotifier(target Notifier, window time.Duration) *DigestNotifier {
+	return &DigestNotifier{
+		target: target,
+		window: window,
+		buffer: make(map[string]*digestBucket),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start begins the background flush loop, emitting a digest every window
+// until Stop is called. Must be called at most once per DigestNotifier.
+func (d *DigestNotifier) Start() {
+	go d.run()
+}
+
+// Stop ends the flush loop. Whatever is buffered at that point is left
+// unsent.
+func (d *DigestNotifier) Stop() {
+	close(d.stopCh)
+}
+
+func (d *DigestNotifier) run() {
+	ticker := time.NewTicker(d.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.flush()
+		case <-d.stopCh:
+			return
+		}
+	}
+}
+
+func (d *DigestNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	return d.target.NotifySentinelOneDetection(alert, enriched)
+}
+
+func (d *DigestNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	return d.target.NotifyHighConfidenceIOC(ioc)
+}
+
+func (d *DigestNotifier) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	return d.target.NotifySupplyChainThreat(pkg)
+}
+
+func (d *DigestNotifier) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triageResult *TriageResult) error {
+	if triageResult == nil || !digestSeverities[strings.ToLower(triageResult.Severity)] {
+		return d.target.NotifySentinelOneDetectionWithTriage(alert, enriched, triageResult)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := alert.Endpoint.ComputerName + "|" + alert.ThreatName
+	b, ok := d.buffer[key]
+	if !ok {
+		b = &digestBucket{
+			entry:    DigestEntry{Endpoint: alert.Endpoint.ComputerName, Threat: alert.ThreatName, Severity: triageResult.Severity},
+			alert:    alert,
+			enriched: enriched,
+			triage:   triageResult,
+		}
+		d.buffer[key] = b
+	}
+	b.entry.Count++
+	RecordDigestBuffered()
+	return nil
+}
+
+// flush sends whatever's buffered as a single digest (via DigestTarget, if
+// target supports it) or, failing that, resends each group's first alert
+// individually so nothing buffered silently disappears.
+func (d *DigestNotifier) flush() {
+	d.mu.Lock()
+	if len(d.buffer) == 0 {
+		d.mu.Unlock()
+		return
+	}
+	buckets := d.buffer
+	d.buffer = make(map[string]*digestBucket)
+	d.mu.Unlock()
+
+	entries := make([]DigestEntry, 0, len(buckets))
+	for _, b := range buckets {
+		entries = append(entries, b.entry)
+	}
+
+	if dt, ok := d.target.(DigestTarget); ok {
+		if err := dt.NotifyDigest(entries); err != nil {
+			log.Printf("❌ failed to send alert digest: %v", err)
+		}
+		RecordDigestFlushed(len(entries))
+		return
+	}
+
+	log.Printf("⚠️  %T doesn't implement notifier.DigestTarget, falling back to one alert per group", d.target)
+	for _, b := range buckets {
+		if err := d.target.NotifySentinelOneDetectionWithTriage(b.alert, b.enriched, b.triage); err != nil {
+			log.Printf("❌ failed to send fallback digest alert: %v", err)
+		}
+	}
+	RecordDigestFlushed(len(entries))
+}
@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routableSeverities are the NOTIFY_ROUTE_<SEVERITY> env vars BuildFromEnv
+// checks for, matching the values TriageResult.Severity is set to
+// elsewhere in the pipeline.
+var routableSeverities = []string{"critical", "high", "medium", "low", "info"}
+
+// BuildFromEnv assembles a Notifier from whichever channels are configured
+// via env vars:
+//
+//   - SLACK_BOT_TOKEN / SLACK_CHANNEL_SECURITY / SLACK_MENTION_TEAM (see
+//     ConfigureFromEnv for per-alert-type identity/template overrides)
+//   - TELEGRAM_BOT_TOKEN / TELEGRAM_CHAT_ID
+//   - WEBHOOK_URL / WEBHOOK_BODY_TEMPLATE (headers are not settable via env
+//   - build a WebhookNotifier directly for those)
+//   - TEAMS_WEBHOOK_URL
+//
+// and wires per-severity routing from NOTIFY_ROUTE_<SEVERITY>, a
+// comma-separated list of channel names (slack, telegram, webhook, teams)
+// that should receive triaged SentinelOne alerts of that severity instead
+// of every configured channel. Returns (nil, nil) if no channel is
+// configured, so callers can treat a nil Notifier as "notifications
+// disabled".
+//
+// Two more layers wrap the result if configured:
+//
+//   - NOTIFY_DEDUPE_WINDOW_MINUTES wraps it in a Deduper, persisting to a
+//     BoltDedupeStore at NOTIFY_DEDUPE_BOLT_PATH if set, or an in-memory
+//     store otherwise.
+//   - NOTIFY_DIGEST_WINDOW_MINUTES wraps (the possibly-deduped) result in a
+//     DigestNotifier that consolidates low/medium severity alerts.
+func BuildFromEnv() (Notifier, error) {
+	channels := make(map[string]Notifier)
+
+	if token := os.Getenv("SLACK_BOT_TOKEN"); token != "" {
+		s := NewSlackNotifier(token,
+			getEnvOr("SLACK_CHANNEL_SECURITY", "#security-alerts"),
+			getEnvOr("SLACK_MENTION_TEAM", "@security-team"))
+		if err := ConfigureFromEnv(s); err != nil {
+			return nil, fmt.Errorf("slack: %w", err)
+		}
+		channels["slack"] = s
+	}
+
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		channels["telegram"] = NewTelegramNotifier(token, os.Getenv("TELEGRAM_CHAT_ID"))
+	}
+
+	if url := os.Getenv("WEBHOOK_URL"); url != "" {
+		w, err := NewWebhookNotifier(url, nil, os.Getenv("WEBHOOK_BODY_TEMPLATE"))
+		if err != nil {
+			return nil, fmt.Errorf("webhook: %w", err)
+		}
+		channels["webhook"] = w
+	}
+
+	if url := os.Getenv("TEAMS_WEBHOOK_URL"); url != "" {
+		channels["teams"] = NewTeamsNotifier(url)
+	}
+
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	all := make([]Notifier, 0, len(channels))
+	for _, n := range channels {
+		all = append(all, n)
+	}
+	router := NewRouter(all...)
+
+	for _, severity := range routableSeverities {
+		names := os.Getenv("NOTIFY_ROUTE_" + strings.ToUpper(severity))
+		if names == "" {
+			continue
+		}
+
+		var route []Notifier
+		for _, name := range strings.Split(names, ",") {
+			if n, ok := channels[strings.TrimSpace(name)]; ok {
+				route = append(route, n)
+			}
+		}
+		if len(route) > 0 {
+			router.Route(severity, route...)
+		}
+	}
+
+	var result Notifier = router
+
+	// Deduplication (optional - suppresses repeat alerts within a window)
+	if windowMinutes := getEnvInt("NOTIFY_DEDUPE_WINDOW_MINUTES", 0); windowMinutes > 0 {
+		store, err := dedupeStoreFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("dedupe: %w", err)
+		}
+		result = NewDeduper(result, store, time.Duration(windowMinutes)*time.Minute)
+	}
+
+	// Digest batching (optional - consolidates low/medium severity alerts)
+	if digestMinutes := getEnvInt("NOTIFY_DIGEST_WINDOW_MINUTES", 0); digestMinutes > 0 {
+		digest := NewDigestNotifier(result, time.Duration(digestMinutes)*time.Minute)
+		digest.Start()
+		result = digest
+	}
+
+	return result, nil
+}
+
+// dedupeStoreFromEnv builds the DedupeStore BuildFromEnv's Deduper persists
+// to: a BoltDedupeStore at NOTIFY_DEDUPE_BOLT_PATH if set, so the dedupe
+// window survives a restart, or an in-memory store otherwise.
+func dedupeStoreFromEnv() (DedupeStore, error) {
+	if path := os.Getenv("NOTIFY_DEDUPE_BOLT_PATH"); path != "" {
+		store, err := NewBoltDedupeStore(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open NOTIFY_DEDUPE_BOLT_PATH: %w", err)
+		}
+		return store, nil
+	}
+	return NewInMemoryDedupeStore(), nil
+}
+
+func getEnvOr(key, defaultValue string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if val := os.Getenv(key); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
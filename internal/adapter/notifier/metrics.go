@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// metricsOnce ensures metrics are registered only once
+	metricsOnce sync.Once
+
+	// notifierDedupeSuppressedTotal tracks alerts Deduper dropped as
+	// repeats of a fingerprint already seen within its window
+	notifierDedupeSuppressedTotal prometheus.Counter
+
+	// notifierDigestBufferedTotal tracks alerts DigestNotifier folded into
+	// a pending digest instead of sending immediately
+	notifierDigestBufferedTotal prometheus.Counter
+
+	// notifierDigestFlushedTotal tracks alert groups included in a flushed digest
+	notifierDigestFlushedTotal prometheus.Counter
+)
+
+// InitMetrics registers all Prometheus metrics for the notifier package.
+// This should be called once at application startup.
+func InitMetrics() {
+	metricsOnce.Do(func() {
+		notifierDedupeSuppressedTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "notifier_dedupe_suppressed_total",
+				Help: "Total number of alerts suppressed by Deduper as repeats within its window",
+			},
+		)
+
+		notifierDigestBufferedTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "notifier_digest_buffered_total",
+				Help: "Total number of low/medium severity alerts buffered into a pending digest",
+			},
+		)
+
+		notifierDigestFlushedTotal = promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "notifier_digest_flushed_total",
+				Help: "Total number of alert groups included in a flushed digest",
+			},
+		)
+	})
+}
+
+// RecordSuppressed records an alert suppressed by Deduper as a duplicate.
+func RecordSuppressed() {
+	if notifierDedupeSuppressedTotal != nil {
+		notifierDedupeSuppressedTotal.Inc()
+	}
+}
+
+// RecordDigestBuffered records a low/medium severity alert folded into a
+// pending digest.
+func RecordDigestBuffered() {
+	if notifierDigestBufferedTotal != nil {
+		notifierDigestBufferedTotal.Inc()
+	}
+}
+
+// RecordDigestFlushed records groupCount alert groups included in a flushed digest.
+func RecordDigestFlushed(groupCount int) {
+	if notifierDigestFlushedTotal != nil {
+		notifierDigestFlushedTotal.Add(float64(groupCount))
+	}
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notifier is the channel-agnostic interface every notification backend
+// (Slack, Telegram, a generic webhook, Teams, ...) implements, so callers
+// depend on "a place to send alerts" rather than a concrete transport.
+type Notifier interface {
+	NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error
+	NotifyHighConfidenceIOC(ioc IOCNotification) error
+	NotifySupplyChainThreat(pkg SupplyChainThreat) error
+	NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triageResult *TriageResult) error
+}
+
+// MultiNotifier fans every notification out to each of its backends, so a
+// call site can send to Slack, Telegram, and a webhook at once without
+// knowing how many channels are actually configured.
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+// NewMultiNotifier returns a MultiNotifier that fans out to notifiers, in
+// the order given.
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{notifiers: notifiers}
+}
+
+func (m *MultiNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	return m.dispatch(func(n Notifier) error { return n.NotifySentinelOneDetection(alert, enriched) })
+}
+
+func (m *MultiNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	return m.dispatch(func(n Notifier) error { return n.NotifyHighConfidenceIOC(ioc) })
+}
+
+func (m *MultiNotifier) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	return m.dispatch(func(n Notifier) error { return n.NotifySupplyChainThreat(pkg) })
+}
+
+func (m *MultiNotifier) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triageResult *TriageResult) error {
+	return m.dispatch(func(n Notifier) error {
+		return n.NotifySentinelOneDetectionWithTriage(alert, enriched, triageResult)
+	})
+}
+
+// dispatch calls fn against every backend, continuing past individual
+// failures (a down Telegram bot shouldn't stop the Slack page from going
+// out) and joining whatever errors occurred into one.
+func (m *MultiNotifier) dispatch(fn func(Notifier) error) error {
+	var errs []string
+	for _, n := range m.notifiers {
+		if err := fn(n); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notifier errors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package notifier
+
+import "strings"
+
+// Router routes notifications by triage severity, so e.g. critical alerts
+// can page Slack + Telegram while low-severity ones only land in a
+// webhook for later review. Only NotifySentinelOneDetectionWithTriage
+// carries a severity; the other Notify* calls always use Default.
+type Router struct {
+	// Default is used for NotifySentinelOneDetection, NotifyHighConfidenceIOC,
+	// and NotifySupplyChainThreat, and as the fallback for any severity
+	// without its own route.
+	Default *MultiNotifier
+
+	bySeverity map[string]*MultiNotifier
+}
+
+// NewRouter returns a Router whose Default fans out to defaultNotifiers.
+// Use Route to add per-severity overrides.
+func NewRouter(defaultNotifiers ...Notifier) *Router {
+	return &Router{
+		Default:    NewMultiNotifier(defaultNotifiers...),
+		bySeverity: make(map[string]*MultiNotifier),
+	}
+}
+
+// Route installs notifiers as the destinations for triage results whose
+// Severity matches severity (case-insensitive), replacing Default for that
+// severity rather than adding to it.
+func (r *Router) Route(severity string, notifiers ...Notifier) {
+	r.bySeverity[strings.ToLower(severity)] = NewMultiNotifier(notifiers...)
+}
+
+func (r *Router) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	return r.Default.NotifySentinelOneDetection(alert, enriched)
+}
+
+func (r *Router) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	return r.Default.NotifyHighConfidenceIOC(ioc)
+}
+
+func (r *Router) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	return r.Default.NotifySupplyChainThreat(pkg)
+}
+
+func (r *Router) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triageResult *TriageResult) error {
+	target := r.Default
+	if triageResult != nil {
+		if route, ok := r.bySeverity[strings.ToLower(triageResult.Severity)]; ok {
+			target = route
+		}
+	}
+	return target.NotifySentinelOneDetectionWithTriage(alert, enriched, triageResult)
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"text/template"
 	"time"
 )
 
@@ -14,6 +16,12 @@ type SlackNotifier struct {
 	channel     string
 	mentionTeam string
 	httpClient  *http.Client
+
+	// identities and templates hold per-alert-type overrides installed via
+	// SetIdentity/SetTemplate; both are nil until the first Set call, and a
+	// missing entry means "use the bot's default identity / built-in blocks".
+	identities map[SlackAlertType]SlackIdentity
+	templates  map[SlackAlertType]*Template
 }
 
 func NewSlackNotifier(botToken, channel, mentionTeam string) *SlackNotifier {
@@ -27,17 +35,190 @@ func NewSlackNotifier(botToken, channel, mentionTeam string) *SlackNotifier {
 	}
 }
 
+// SlackAlertType identifies which notification kind a per-type Identity or
+// Template override applies to.
+type SlackAlertType string
+
+const (
+	SlackAlertSentinelOne SlackAlertType = "sentinelone"
+	SlackAlertIOC         SlackAlertType = "ioc"
+	SlackAlertSupplyChain SlackAlertType = "supplychain"
+)
+
+// SlackIdentity overrides the bot username/icon a notification is posted
+// as, letting operators route different alert types to different bot
+// personas instead of the app's default identity.
+type SlackIdentity struct {
+	Username  string
+	IconEmoji string // e.g. ":rotating_light:"
+	IconURL   string
+}
+
+// SetIdentity installs identity as the username/icon used for alertType's
+// notifications from now on. Fields left empty fall back to the bot's
+// default identity for that field.
+func (s *SlackNotifier) SetIdentity(alertType SlackAlertType, identity SlackIdentity) {
+	if s.identities == nil {
+		s.identities = make(map[SlackAlertType]SlackIdentity)
+	}
+	s.identities[alertType] = identity
+}
+
+// SetTemplate installs tmpl to render alertType's header, mention line, and
+// recommended-actions section from now on. A nil tmpl (or a Template with a
+// given section left unset) falls back to the built-in wording for that
+// section.
+func (s *SlackNotifier) SetTemplate(alertType SlackAlertType, tmpl *Template) {
+	if s.templates == nil {
+		s.templates = make(map[SlackAlertType]*Template)
+	}
+	s.templates[alertType] = tmpl
+}
+
+// payloadFor builds the Slack API payload for alertType, applying any
+// identity override installed via SetIdentity.
+func (s *SlackNotifier) payloadFor(alertType SlackAlertType, blocks []SlackBlock, fallbackText string) SlackMessage {
+	msg := SlackMessage{
+		Channel: s.channel,
+		Blocks:  blocks,
+		Text:    fallbackText,
+	}
+	if id, ok := s.identities[alertType]; ok {
+		msg.Username = id.Username
+		msg.IconEmoji = id.IconEmoji
+		msg.IconURL = id.IconURL
+	}
+	return msg
+}
+
+// Template customizes the header text, mention line, and recommended-actions
+// section of a notification via text/template, so operators can brand or
+// reroute alerts (e.g. by severity) without editing the build*Blocks
+// methods. Each section is independent; a nil *template.Template leaves
+// that section to fall back to the built-in wording.
+type Template struct {
+	Header  *template.Template
+	Mention *template.Template
+	Actions *template.Template
+}
+
+// TemplateData is what a Template's sections are rendered against: the
+// triggering alert (a SentinelOneAlert, IOCNotification, or
+// SupplyChainThreat depending on notification type), any enriched
+// indicators, the team to mention, and - for triaged SentinelOne alerts -
+// the LLM's TriageResult.
+type TemplateData struct {
+	Alert       interface{}
+	Enriched    []EnrichedIndicator
+	Triage      *TriageResult
+	MentionTeam string
+}
+
+// NewTemplate compiles header, mention, and actions as independent
+// text/template sources. An empty string leaves that section unset so
+// callers can override just one piece and fall back to the built-in
+// wording for the rest.
+func NewTemplate(header, mention, actions string) (*Template, error) {
+	t := &Template{}
+	var err error
+	if header != "" {
+		if t.Header, err = template.New("header").Parse(header); err != nil {
+			return nil, fmt.Errorf("invalid header template: %w", err)
+		}
+	}
+	if mention != "" {
+		if t.Mention, err = template.New("mention").Parse(mention); err != nil {
+			return nil, fmt.Errorf("invalid mention template: %w", err)
+		}
+	}
+	if actions != "" {
+		if t.Actions, err = template.New("actions").Parse(actions); err != nil {
+			return nil, fmt.Errorf("invalid actions template: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// renderHeader, renderMention, and renderActions render their section
+// against data, reporting ok=false (so the caller falls back to the
+// built-in text) when tmpl is nil, the section wasn't configured, or
+// rendering fails.
+func (t *Template) renderHeader(data TemplateData) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	return renderSection(t.Header, data)
+}
+
+func (t *Template) renderMention(data TemplateData) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	return renderSection(t.Mention, data)
+}
+
+func (t *Template) renderActions(data TemplateData) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	return renderSection(t.Actions, data)
+}
+
+func renderSection(tmpl *template.Template, data TemplateData) (string, bool) {
+	if tmpl == nil {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// ConfigureFromEnv installs per-alert-type identity and template overrides
+// on s from SLACK_<TYPE>_USERNAME / _ICON_EMOJI / _ICON_URL and
+// SLACK_<TYPE>_HEADER_TEMPLATE / _MENTION_TEMPLATE / _ACTIONS_TEMPLATE,
+// where <TYPE> is SENTINELONE, IOC, or SUPPLYCHAIN. A type with none of its
+// env vars set keeps the bot's default identity and built-in blocks.
+func ConfigureFromEnv(s *SlackNotifier) error {
+	prefixes := map[SlackAlertType]string{
+		SlackAlertSentinelOne: "SLACK_SENTINELONE_",
+		SlackAlertIOC:         "SLACK_IOC_",
+		SlackAlertSupplyChain: "SLACK_SUPPLYCHAIN_",
+	}
+
+	for alertType, prefix := range prefixes {
+		identity := SlackIdentity{
+			Username:  os.Getenv(prefix + "USERNAME"),
+			IconEmoji: os.Getenv(prefix + "ICON_EMOJI"),
+			IconURL:   os.Getenv(prefix + "ICON_URL"),
+		}
+		if identity.Username != "" || identity.IconEmoji != "" || identity.IconURL != "" {
+			s.SetIdentity(alertType, identity)
+		}
+
+		header := os.Getenv(prefix + "HEADER_TEMPLATE")
+		mention := os.Getenv(prefix + "MENTION_TEMPLATE")
+		actions := os.Getenv(prefix + "ACTIONS_TEMPLATE")
+		if header != "" || mention != "" || actions != "" {
+			tmpl, err := NewTemplate(header, mention, actions)
+			if err != nil {
+				return fmt.Errorf("%s templates: %w", alertType, err)
+			}
+			s.SetTemplate(alertType, tmpl)
+		}
+	}
+
+	return nil
+}
+
 // NotifySentinelOneDetection sends formatted alert to Slack
 func (s *SlackNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
 	// Build Slack message blocks
 	blocks := s.buildSentinelOneBlocks(alert, enriched)
 
-	// Slack API payload
-	payload := SlackMessage{
-		Channel: s.channel,
-		Blocks:  blocks,
-		Text:    fmt.Sprintf("⚠️ Threat detected on %s", alert.Endpoint.ComputerName),
-	}
+	payload := s.payloadFor(SlackAlertSentinelOne, blocks,
+		fmt.Sprintf("⚠️ Threat detected on %s", alert.Endpoint.ComputerName))
 
 	return s.sendMessage(payload)
 }
@@ -46,11 +227,8 @@ func (s *SlackNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enric
 func (s *SlackNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
 	blocks := s.buildHighConfidenceIOCBlocks(ioc)
 
-	payload := SlackMessage{
-		Channel: s.channel,
-		Blocks:  blocks,
-		Text:    fmt.Sprintf("🚨 High-confidence IOC detected: %s", ioc.Value),
-	}
+	payload := s.payloadFor(SlackAlertIOC, blocks,
+		fmt.Sprintf("🚨 High-confidence IOC detected: %s", ioc.Value))
 
 	return s.sendMessage(payload)
 }
@@ -59,24 +237,57 @@ func (s *SlackNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
 func (s *SlackNotifier) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
 	blocks := s.buildSupplyChainBlocks(pkg)
 
-	payload := SlackMessage{
-		Channel: s.channel,
-		Blocks:  blocks,
-		Text:    fmt.Sprintf("📦 Supply chain threat: %s", pkg.PackageName),
-	}
+	payload := s.payloadFor(SlackAlertSupplyChain, blocks,
+		fmt.Sprintf("📦 Supply chain threat: %s", pkg.PackageName))
 
 	return s.sendMessage(payload)
 }
 
+// Slack action_ids for the SentinelOne triage buttons, dispatched by
+// notifier/interactions to the matching handler.
+const (
+	SlackActionAcknowledge       = "sentinelone_acknowledge"
+	SlackActionIsolateEndpoint   = "sentinelone_isolate_endpoint"
+	SlackActionMarkFalsePositive = "sentinelone_mark_false_positive"
+	SlackActionEscalate          = "sentinelone_escalate"
+)
+
+// buildActionsBlock renders the SOC triage buttons (Acknowledge, Isolate
+// endpoint, Mark false positive, Escalate) for a SentinelOne alert, each
+// carrying alertID as its value so notifier/interactions can route the
+// click back to the right alert.
+func buildActionsBlock(alertID string) SlackBlock {
+	button := func(text, actionID, style string) SlackText {
+		return SlackText{Type: "button", Text: text, ActionID: actionID, Value: alertID, Style: style}
+	}
+	return SlackBlock{
+		Type: "actions",
+		Elements: []SlackText{
+			button("Acknowledge", SlackActionAcknowledge, ""),
+			button("Isolate endpoint", SlackActionIsolateEndpoint, "danger"),
+			button("Mark false positive", SlackActionMarkFalsePositive, ""),
+			button("Escalate", SlackActionEscalate, "primary"),
+		},
+	}
+}
+
 // Build Slack message blocks for SentinelOne detection
 func (s *SlackNotifier) buildSentinelOneBlocks(alert SentinelOneAlert, enriched []EnrichedIndicator) []SlackBlock {
+	tmpl := s.templates[SlackAlertSentinelOne]
+	data := TemplateData{Alert: alert, Enriched: enriched, MentionTeam: s.mentionTeam}
+
+	headerText := "⚠️ Threat Detection Alert"
+	if rendered, ok := tmpl.renderHeader(data); ok {
+		headerText = rendered
+	}
+
 	blocks := []SlackBlock{
 		// Header
 		{
 			Type: "header",
 			Text: &SlackText{
 				Type: "plain_text",
-				Text: "⚠️ Threat Detection Alert",
+				Text: headerText,
 			},
 		},
 		// Alert details
@@ -122,29 +333,52 @@ func (s *SlackNotifier) buildSentinelOneBlocks(alert SentinelOneAlert, enriched
 	}
 
 	// Recommended actions
+	actionsText := fmt.Sprintf("*Recommended Actions*\n✓ Isolate endpoint %s\n✓ Investigate recent activity\n✓ Scan other endpoints",
+		alert.Endpoint.ComputerName)
+	if rendered, ok := tmpl.renderActions(data); ok {
+		actionsText = rendered
+	}
 	blocks = append(blocks,
 		SlackBlock{Type: "divider"},
 		SlackBlock{
 			Type: "section",
-			Text: &SlackText{
-				Type: "mrkdwn",
-				Text: fmt.Sprintf("*Recommended Actions*\n✓ Isolate endpoint %s\n✓ Investigate recent activity\n✓ Scan other endpoints\n\ncc: %s",
-					alert.Endpoint.ComputerName, s.mentionTeam),
-			},
+			Text: &SlackText{Type: "mrkdwn", Text: actionsText},
 		},
 	)
 
+	// Mention line
+	mentionText := fmt.Sprintf("cc: %s", s.mentionTeam)
+	if rendered, ok := tmpl.renderMention(data); ok {
+		mentionText = rendered
+	}
+	if mentionText != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: mentionText},
+		})
+	}
+
+	blocks = append(blocks, buildActionsBlock(alert.AlertID))
+
 	return blocks
 }
 
 // Build Slack blocks for high-confidence IOC
 func (s *SlackNotifier) buildHighConfidenceIOCBlocks(ioc IOCNotification) []SlackBlock {
-	return []SlackBlock{
+	tmpl := s.templates[SlackAlertIOC]
+	data := TemplateData{Alert: ioc, MentionTeam: s.mentionTeam}
+
+	headerText := "🚨 High-Confidence IOC Detected"
+	if rendered, ok := tmpl.renderHeader(data); ok {
+		headerText = rendered
+	}
+
+	blocks := []SlackBlock{
 		{
 			Type: "header",
 			Text: &SlackText{
 				Type: "plain_text",
-				Text: "🚨 High-Confidence IOC Detected",
+				Text: headerText,
 			},
 		},
 		{
@@ -160,10 +394,30 @@ func (s *SlackNotifier) buildHighConfidenceIOCBlocks(ioc IOCNotification) []Slac
 			Type: "section",
 			Text: &SlackText{
 				Type: "mrkdwn",
-				Text: fmt.Sprintf("*Tags*: %s\n\ncc: %s", strings.Join(ioc.Tags, ", "), s.mentionTeam),
+				Text: fmt.Sprintf("*Tags*: %s", strings.Join(ioc.Tags, ", ")),
 			},
 		},
 	}
+
+	if rendered, ok := tmpl.renderActions(data); ok {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: rendered},
+		})
+	}
+
+	mentionText := fmt.Sprintf("cc: %s", s.mentionTeam)
+	if rendered, ok := tmpl.renderMention(data); ok {
+		mentionText = rendered
+	}
+	if mentionText != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: mentionText},
+		})
+	}
+
+	return blocks
 }
 
 // Build Slack blocks for supply chain threat
@@ -173,12 +427,20 @@ func (s *SlackNotifier) buildSupplyChainBlocks(pkg SupplyChainThreat) []SlackBlo
 		versionText = "All versions"
 	}
 
-	return []SlackBlock{
+	tmpl := s.templates[SlackAlertSupplyChain]
+	data := TemplateData{Alert: pkg, MentionTeam: s.mentionTeam}
+
+	headerText := "📦 Supply Chain Threat Detected"
+	if rendered, ok := tmpl.renderHeader(data); ok {
+		headerText = rendered
+	}
+
+	blocks := []SlackBlock{
 		{
 			Type: "header",
 			Text: &SlackText{
 				Type: "plain_text",
-				Text: "📦 Supply Chain Threat Detected",
+				Text: headerText,
 			},
 		},
 		{
@@ -194,11 +456,71 @@ func (s *SlackNotifier) buildSupplyChainBlocks(pkg SupplyChainThreat) []SlackBlo
 			Type: "section",
 			Text: &SlackText{
 				Type: "mrkdwn",
-				Text: fmt.Sprintf("*Threat Type*: %s\n*Tags*: %s\n\n🔒 *Action Required*: Block this package in CI/CD pipelines\n\ncc: %s @devops",
-					pkg.ThreatType, strings.Join(pkg.Tags, ", "), s.mentionTeam),
+				Text: fmt.Sprintf("*Threat Type*: %s\n*Tags*: %s", pkg.ThreatType, strings.Join(pkg.Tags, ", ")),
 			},
 		},
 	}
+
+	actionsText := "🔒 *Action Required*: Block this package in CI/CD pipelines"
+	if rendered, ok := tmpl.renderActions(data); ok {
+		actionsText = rendered
+	}
+	blocks = append(blocks, SlackBlock{
+		Type: "section",
+		Text: &SlackText{Type: "mrkdwn", Text: actionsText},
+	})
+
+	mentionText := fmt.Sprintf("cc: %s @devops", s.mentionTeam)
+	if rendered, ok := tmpl.renderMention(data); ok {
+		mentionText = rendered
+	}
+	if mentionText != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: mentionText},
+		})
+	}
+
+	return blocks
+}
+
+// NotifyDigest sends a single consolidated Slack message summarizing
+// entries (endpoint, threat, alert count) in place of one message per
+// low/medium-severity alert. Implements notifier.DigestTarget.
+func (s *SlackNotifier) NotifyDigest(entries []DigestEntry) error {
+	blocks := s.buildDigestBlocks(entries)
+
+	payload := s.payloadFor(SlackAlertSentinelOne, blocks,
+		fmt.Sprintf("🗂 Alert digest: %d group(s)", len(entries)))
+
+	return s.sendMessage(payload)
+}
+
+// buildDigestBlocks renders entries as a single header plus a mrkdwn table
+// (endpoint, threat, count), one row per group.
+func (s *SlackNotifier) buildDigestBlocks(entries []DigestEntry) []SlackBlock {
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: fmt.Sprintf("🗂 Alert Digest (%d groups)", len(entries)),
+			},
+		},
+	}
+
+	rows := make([]string, 0, len(entries)+1)
+	rows = append(rows, "*Endpoint* | *Threat* | *Count*")
+	for _, e := range entries {
+		rows = append(rows, fmt.Sprintf("%s | %s | %d", e.Endpoint, e.Threat, e.Count))
+	}
+
+	blocks = append(blocks, SlackBlock{
+		Type: "section",
+		Text: &SlackText{Type: "mrkdwn", Text: strings.Join(rows, "\n")},
+	})
+
+	return blocks
 }
 
 // Send message to Slack
@@ -232,9 +554,12 @@ func (s *SlackNotifier) sendMessage(msg SlackMessage) error {
 // Slack API structures
 
 type SlackMessage struct {
-	Channel string       `json:"channel"`
-	Blocks  []SlackBlock `json:"blocks"`
-	Text    string       `json:"text"` // Fallback text
+	Channel   string       `json:"channel"`
+	Blocks    []SlackBlock `json:"blocks"`
+	Text      string       `json:"text"` // Fallback text
+	Username  string       `json:"username,omitempty"`
+	IconEmoji string       `json:"icon_emoji,omitempty"`
+	IconURL   string       `json:"icon_url,omitempty"`
 }
 
 type SlackBlock struct {
@@ -247,6 +572,13 @@ type SlackBlock struct {
 type SlackText struct {
 	Type string `json:"type"`
 	Text string `json:"text"`
+
+	// ActionID, Value, and Style are only set when this SlackText represents
+	// a button inside an "actions" block element rather than a text object;
+	// omitted (and ignored by Slack) everywhere else.
+	ActionID string `json:"action_id,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Style    string `json:"style,omitempty"` // "primary", "danger", or "" for the default button
 }
 
 // Notification data structures
@@ -293,12 +625,8 @@ func (s *SlackNotifier) NotifySentinelOneDetectionWithTriage(alert SentinelOneAl
 	// Build Slack message blocks with LLM insights
 	blocks := s.buildSentinelOneBlocksWithTriage(alert, enriched, triageResult)
 
-	// Slack API payload
-	payload := SlackMessage{
-		Channel: s.channel,
-		Blocks:  blocks,
-		Text:    fmt.Sprintf("⚠️ %s: Threat detected on %s", strings.ToUpper(triageResult.Severity), alert.Endpoint.ComputerName),
-	}
+	payload := s.payloadFor(SlackAlertSentinelOne, blocks,
+		fmt.Sprintf("⚠️ %s: Threat detected on %s", strings.ToUpper(triageResult.Severity), alert.Endpoint.ComputerName))
 
 	return s.sendMessage(payload)
 }
@@ -318,13 +646,21 @@ func (s *SlackNotifier) buildSentinelOneBlocksWithTriage(alert SentinelOneAlert,
 		emoji = "⚠️"
 	}
 
+	tmpl := s.templates[SlackAlertSentinelOne]
+	data := TemplateData{Alert: alert, Enriched: enriched, Triage: triage, MentionTeam: s.mentionTeam}
+
+	headerText := fmt.Sprintf("%s %s Severity Threat Detected", emoji, strings.ToUpper(triage.Severity))
+	if rendered, ok := tmpl.renderHeader(data); ok {
+		headerText = rendered
+	}
+
 	blocks := []SlackBlock{
 		// Header with severity
 		{
 			Type: "header",
 			Text: &SlackText{
 				Type: "plain_text",
-				Text: fmt.Sprintf("%s %s Severity Threat Detected", emoji, strings.ToUpper(triage.Severity)),
+				Text: headerText,
 			},
 		},
 
@@ -409,7 +745,12 @@ func (s *SlackNotifier) buildSentinelOneBlocksWithTriage(alert SentinelOneAlert,
 	blocks = append(blocks, SlackBlock{Type: "divider"})
 
 	// Recommended actions
-	if len(triage.Recommended) > 0 {
+	if rendered, ok := tmpl.renderActions(data); ok {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: rendered},
+		})
+	} else if len(triage.Recommended) > 0 {
 		recommendedText := "*✅ Recommended Actions*\n"
 		for _, action := range triage.Recommended {
 			recommendedText += fmt.Sprintf("• %s\n", action)
@@ -445,7 +786,12 @@ func (s *SlackNotifier) buildSentinelOneBlocksWithTriage(alert SentinelOneAlert,
 	})
 
 	// Mention team if configured
-	if s.mentionTeam != "" {
+	if rendered, ok := tmpl.renderMention(data); ok {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: rendered},
+		})
+	} else if s.mentionTeam != "" {
 		blocks = append(blocks, SlackBlock{
 			Type: "section",
 			Text: &SlackText{
@@ -455,6 +801,8 @@ func (s *SlackNotifier) buildSentinelOneBlocksWithTriage(alert SentinelOneAlert,
 		})
 	}
 
+	blocks = append(blocks, buildActionsBlock(alert.AlertID))
+
 	return blocks
 }
 
@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TeamsNotifier posts Adaptive Cards to a Microsoft Teams incoming webhook
+// connector, for SOC teams whose primary chat tool is Teams rather than
+// Slack or Telegram.
+type TeamsNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewTeamsNotifier returns a notifier that posts to webhookURL, the URL
+// issued by a Teams channel's "Incoming Webhook" connector.
+func NewTeamsNotifier(webhookURL string) *TeamsNotifier {
+	return &TeamsNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (t *TeamsNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	body := []teamsElement{
+		{Type: "TextBlock", Text: "⚠️ Threat Detection Alert", Weight: "bolder", Size: "large"},
+		{Type: "FactSet", Facts: []teamsFact{
+			{Title: "Endpoint", Value: alert.Endpoint.ComputerName},
+			{Title: "OS Type", Value: alert.Endpoint.OSType},
+			{Title: "Threat", Value: alert.ThreatName},
+			{Title: "Classification", Value: alert.Classification},
+		}},
+	}
+
+	for _, ind := range enriched {
+		text := fmt.Sprintf("**%s**: `%s`", ind.Type, ind.Value)
+		if ind.InDatabase {
+			text += fmt.Sprintf("\n\nSources: %s", strings.Join(ind.Sources, ", "))
+		} else {
+			text += "\n\nNot found in Watchtower database"
+		}
+		body = append(body, teamsElement{Type: "TextBlock", Text: text, Wrap: true})
+	}
+
+	return t.send(body)
+}
+
+func (t *TeamsNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	body := []teamsElement{
+		{Type: "TextBlock", Text: "🚨 High-Confidence IOC Detected", Weight: "bolder", Size: "large"},
+		{Type: "FactSet", Facts: []teamsFact{
+			{Title: "Value", Value: ioc.Value},
+			{Title: "Type", Value: ioc.Type},
+			{Title: "Confidence", Value: fmt.Sprintf("%d/100", ioc.Confidence)},
+			{Title: "Sources", Value: strings.Join(ioc.Sources, ", ")},
+			{Title: "Tags", Value: strings.Join(ioc.Tags, ", ")},
+		}},
+	}
+
+	return t.send(body)
+}
+
+func (t *TeamsNotifier) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	versionText := pkg.Version
+	if versionText == "" {
+		versionText = "All versions"
+	}
+
+	body := []teamsElement{
+		{Type: "TextBlock", Text: "📦 Supply Chain Threat Detected", Weight: "bolder", Size: "large"},
+		{Type: "FactSet", Facts: []teamsFact{
+			{Title: "Package", Value: pkg.PackageName},
+			{Title: "Version", Value: versionText},
+			{Title: "Ecosystem", Value: pkg.Ecosystem},
+			{Title: "Source", Value: pkg.Source},
+			{Title: "Threat Type", Value: pkg.ThreatType},
+			{Title: "Tags", Value: strings.Join(pkg.Tags, ", ")},
+		}},
+	}
+
+	return t.send(body)
+}
+
+func (t *TeamsNotifier) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triage *TriageResult) error {
+	body := []teamsElement{
+		{Type: "TextBlock", Text: fmt.Sprintf("%s Severity Threat Detected", strings.ToUpper(triage.Severity)), Weight: "bolder", Size: "large"},
+		{Type: "TextBlock", Text: fmt.Sprintf("**AI Analysis**\n\n%s", triage.Summary), Wrap: true},
+		{Type: "FactSet", Facts: []teamsFact{
+			{Title: "Alert ID", Value: alert.AlertID},
+			{Title: "Threat", Value: alert.ThreatName},
+			{Title: "Endpoint", Value: alert.Endpoint.ComputerName},
+			{Title: "Priority", Value: fmt.Sprintf("P%d", triage.Priority)},
+			{Title: "Confidence", Value: fmt.Sprintf("%d%%", triage.Confidence)},
+		}},
+	}
+
+	if len(triage.Recommended) > 0 {
+		text := "**Recommended Actions**\n\n"
+		for _, action := range triage.Recommended {
+			text += fmt.Sprintf("- %s\n", action)
+		}
+		body = append(body, teamsElement{Type: "TextBlock", Text: text, Wrap: true})
+	}
+
+	return t.send(body)
+}
+
+func (t *TeamsNotifier) send(body []teamsElement) error {
+	card := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: teamsAdaptiveCard{
+					Type:    "AdaptiveCard",
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Version: "1.4",
+					Body:    body,
+				},
+			},
+		},
+	}
+
+	jsonData, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Teams card: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", t.webhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("teams webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Adaptive Card structures - see
+// https://adaptivecards.io/explorer/AdaptiveCard.html
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string            `json:"contentType"`
+	Content     teamsAdaptiveCard `json:"content"`
+}
+
+type teamsAdaptiveCard struct {
+	Type    string         `json:"type"`
+	Schema  string         `json:"$schema"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+}
+
+type teamsElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Size   string      `json:"size,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
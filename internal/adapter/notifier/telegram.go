@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TelegramNotifier sends alerts to a Telegram chat via a bot, for teams
+// that watch a Telegram channel/group instead of (or alongside) Slack.
+type TelegramNotifier struct {
+	botToken   string
+	chatID     string
+	httpClient *http.Client
+}
+
+// NewTelegramNotifier returns a notifier that posts to chatID using botToken,
+// as issued by @BotFather.
+func NewTelegramNotifier(botToken, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		botToken: botToken,
+		chatID:   chatID,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (t *TelegramNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	var sb strings.Builder
+	sb.WriteString("⚠️ *Threat Detection Alert*\n")
+	sb.WriteString(fmt.Sprintf("Endpoint: `%s` (%s)\n", alert.Endpoint.ComputerName, alert.Endpoint.OSType))
+	sb.WriteString(fmt.Sprintf("Threat: %s\nClassification: %s\n", alert.ThreatName, alert.Classification))
+
+	for _, ind := range enriched {
+		if ind.InDatabase {
+			sb.WriteString(fmt.Sprintf("• %s `%s` — sources: %s\n", ind.Type, ind.Value, strings.Join(ind.Sources, ", ")))
+		} else {
+			sb.WriteString(fmt.Sprintf("• %s `%s` — not found in Watchtower database\n", ind.Type, ind.Value))
+		}
+	}
+
+	return t.sendMessage(sb.String())
+}
+
+func (t *TelegramNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	text := fmt.Sprintf("🚨 *High-Confidence IOC Detected*\nValue: `%s`\nType: %s\nConfidence: %d/100\nSources: %s\nTags: %s",
+		ioc.Value, ioc.Type, ioc.Confidence, strings.Join(ioc.Sources, ", "), strings.Join(ioc.Tags, ", "))
+	return t.sendMessage(text)
+}
+
+func (t *TelegramNotifier) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	versionText := pkg.Version
+	if versionText == "" {
+		versionText = "All versions"
+	}
+	text := fmt.Sprintf("📦 *Supply Chain Threat Detected*\nPackage: `%s` (%s)\nEcosystem: %s\nSource: %s\nThreat Type: %s\nTags: %s",
+		pkg.PackageName, versionText, pkg.Ecosystem, pkg.Source, pkg.ThreatType, strings.Join(pkg.Tags, ", "))
+	return t.sendMessage(text)
+}
+
+func (t *TelegramNotifier) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triage *TriageResult) error {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⚠️ *%s Severity Threat Detected*\n", strings.ToUpper(triage.Severity)))
+	sb.WriteString(fmt.Sprintf("Endpoint: `%s`\nThreat: %s\nPriority: P%d\n\n", alert.Endpoint.ComputerName, alert.ThreatName, triage.Priority))
+	sb.WriteString(fmt.Sprintf("AI Analysis: %s\n", triage.Summary))
+
+	if len(triage.Recommended) > 0 {
+		sb.WriteString("\nRecommended Actions:\n")
+		for _, action := range triage.Recommended {
+			sb.WriteString(fmt.Sprintf("• %s\n", action))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\nConfidence: %d%%", triage.Confidence))
+
+	return t.sendMessage(sb.String())
+}
+
+func (t *TelegramNotifier) sendMessage(text string) error {
+	payload := telegramSendMessage{
+		ChatID:    t.chatID,
+		Text:      text,
+		ParseMode: "Markdown",
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Telegram message: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.botToken)
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type telegramSendMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
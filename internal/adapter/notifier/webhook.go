@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+// WebhookNotifier POSTs a JSON payload to an arbitrary URL, for SIEMs,
+// ticketing systems, or other tooling without a dedicated Notifier - the
+// generic escape hatch alongside the named-channel implementations.
+type WebhookNotifier struct {
+	url          string
+	headers      map[string]string
+	bodyTemplate *template.Template // nil uses the default webhookPayload JSON body
+	httpClient   *http.Client
+}
+
+// NewWebhookNotifier returns a notifier that POSTs to url with headers set
+// on every request, in addition to Content-Type: application/json. If
+// bodyTemplate is non-empty, it is compiled as a text/template rendered
+// against TemplateData to produce the request body instead of the default
+// JSON envelope; an empty bodyTemplate keeps the default.
+func NewWebhookNotifier(url string, headers map[string]string, bodyTemplate string) (*WebhookNotifier, error) {
+	w := &WebhookNotifier{
+		url:     url,
+		headers: headers,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+
+	if bodyTemplate != "" {
+		tmpl, err := template.New("webhook_body").Parse(bodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook body template: %w", err)
+		}
+		w.bodyTemplate = tmpl
+	}
+
+	return w, nil
+}
+
+// webhookPayload is the default request body when no bodyTemplate is
+// configured: the raw alert plus whatever enrichment/triage context is
+// available, left for the receiving system to interpret.
+type webhookPayload struct {
+	AlertType string              `json:"alert_type"`
+	Alert     interface{}         `json:"alert"`
+	Enriched  []EnrichedIndicator `json:"enriched,omitempty"`
+	Triage    *TriageResult       `json:"triage,omitempty"`
+}
+
+func (w *WebhookNotifier) NotifySentinelOneDetection(alert SentinelOneAlert, enriched []EnrichedIndicator) error {
+	return w.send("sentinelone", TemplateData{Alert: alert, Enriched: enriched})
+}
+
+func (w *WebhookNotifier) NotifyHighConfidenceIOC(ioc IOCNotification) error {
+	return w.send("ioc", TemplateData{Alert: ioc})
+}
+
+func (w *WebhookNotifier) NotifySupplyChainThreat(pkg SupplyChainThreat) error {
+	return w.send("supplychain", TemplateData{Alert: pkg})
+}
+
+func (w *WebhookNotifier) NotifySentinelOneDetectionWithTriage(alert SentinelOneAlert, enriched []EnrichedIndicator, triageResult *TriageResult) error {
+	return w.send("sentinelone", TemplateData{Alert: alert, Enriched: enriched, Triage: triageResult})
+}
+
+func (w *WebhookNotifier) send(alertType string, data TemplateData) error {
+	var body []byte
+
+	if w.bodyTemplate != nil {
+		var buf bytes.Buffer
+		if err := w.bodyTemplate.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to render webhook body template: %w", err)
+		}
+		body = buf.Bytes()
+	} else {
+		jsonData, err := json.Marshal(webhookPayload{
+			AlertType: alertType,
+			Alert:     data.Alert,
+			Enriched:  data.Enriched,
+			Triage:    data.Triage,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+		body = jsonData
+	}
+
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package observability
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewLogger builds the zap.Logger watchtower's binaries log through, following
+// the same env-driven on/off convention as InitTracer: LOG_LEVEL selects the
+// minimum level (default "info"), and LOG_FORMAT="console" switches from the
+// default JSON encoding to zap's human-readable development encoder for
+// local runs. Every entry is tagged with a "service" field so a shared log
+// sink (e.g. Loki) can filter by binary.
+func NewLogger(serviceName string) (*zap.Logger, error) {
+	level := zapcore.InfoLevel
+	if raw := os.Getenv("LOG_LEVEL"); raw != "" {
+		if err := level.UnmarshalText([]byte(raw)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL %q: %w", raw, err)
+		}
+	}
+
+	var cfg zap.Config
+	if os.Getenv("LOG_FORMAT") == "console" {
+		cfg = zap.NewDevelopmentConfig()
+	} else {
+		cfg = zap.NewProductionConfig()
+	}
+	cfg.Level = zap.NewAtomicLevelAt(level)
+
+	logger, err := cfg.Build(zap.Fields(zap.String("service", serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build zap logger: %w", err)
+	}
+	return logger, nil
+}
@@ -0,0 +1,24 @@
+package observability
+
+import (
+	"context"
+
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewTracedPgxPool opens a pgxpool.Pool the same way pgxpool.New(ctx, dbURL)
+// would, except every query is wrapped in a pgx OTel tracer, so it shows up
+// as a child span of whatever span is already on ctx (the gRPC/HTTP request
+// span, when one is present). The tracer always runs - with no global
+// tracer provider configured (InitTracer never called, or
+// OTEL_EXPORTER_OTLP_ENDPOINT unset) it records against OTel's default
+// no-op provider, so this is safe to call unconditionally.
+func NewTracedPgxPool(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	config.ConnConfig.Tracer = otelpgx.NewTracer()
+	return pgxpool.NewWithConfig(ctx, config)
+}
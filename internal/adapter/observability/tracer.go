@@ -0,0 +1,57 @@
+// Package observability wires OpenTelemetry tracing across the watchtower
+// binaries (gRPC API, REST API, database access) behind a single env-driven
+// on/off switch, following the same opt-in-via-env convention as
+// llm.MTLSConfigFromEnv and rules.EngineFromEnv: with nothing configured,
+// InitTracer is a no-op and the rest of the stack keeps using OTel's default
+// no-op tracer provider.
+package observability
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// InitTracer configures a global OTLP/gRPC tracer provider for serviceName
+// when OTEL_EXPORTER_OTLP_ENDPOINT is set, batching spans to that collector.
+// It returns a shutdown func to flush and close the exporter on graceful
+// shutdown, and (nil, nil) when the env var is unset - callers must check the
+// returned func for nil before deferring it.
+func InitTracer(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	var opts []otlptracegrpc.Option
+	opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+	if os.Getenv("OTEL_EXPORTER_OTLP_INSECURE") == "true" {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OTel resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
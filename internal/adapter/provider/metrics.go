@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// metricsOnce ensures metrics are registered only once
+	metricsOnce sync.Once
+
+	// providerPulsesFetchedTotal tracks pulses/entries fetched per provider run
+	providerPulsesFetchedTotal *prometheus.CounterVec
+
+	// providerIndicatorsIngestedTotal tracks indicators converted to domain.IOC per provider
+	providerIndicatorsIngestedTotal *prometheus.CounterVec
+
+	// providerIndicatorsSkippedTotal tracks indicators dropped per provider, by the
+	// upstream type that had no domain.IOCType mapping
+	providerIndicatorsSkippedTotal *prometheus.CounterVec
+
+	// providerIngestionLagSeconds tracks how stale an indicator already was
+	// (now - FirstSeen) by the time the ingester picked it up, by provider
+	providerIngestionLagSeconds *prometheus.HistogramVec
+
+	// providerFetchDuration tracks how long a provider's FetchIOCS/FetchIOCSSince
+	// call took, by provider and outcome.
+	providerFetchDuration *prometheus.HistogramVec
+
+	// providerLastSuccessTimestamp holds the Unix timestamp of each provider's
+	// last successful fetch, so an alerting rule like
+	// "time() - watchtower_provider_last_success_timestamp_seconds > N*3600"
+	// catches a feed that's gone silent without needing its own staleness logic.
+	providerLastSuccessTimestamp *prometheus.GaugeVec
+)
+
+// InitMetrics registers all Prometheus metrics for threat intel providers.
+// This should be called once at application startup.
+func InitMetrics() {
+	metricsOnce.Do(func() {
+		providerPulsesFetchedTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "watchtower_provider_pulses_fetched_total",
+				Help: "Total number of upstream pulses/entries fetched, by provider",
+			},
+			[]string{"provider"},
+		)
+
+		providerIndicatorsIngestedTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "watchtower_provider_indicators_ingested_total",
+				Help: "Total number of indicators converted to IOCs, by provider",
+			},
+			[]string{"provider"},
+		)
+
+		providerIndicatorsSkippedTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "watchtower_provider_indicators_skipped_total",
+				Help: "Total number of indicators skipped for lacking a known type mapping, by provider and upstream type",
+			},
+			[]string{"provider", "type"},
+		)
+
+		providerIngestionLagSeconds = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_provider_ingestion_lag_seconds",
+				Help:    "Age (now - FirstSeen) of an indicator at the time it was ingested, by provider",
+				Buckets: []float64{60, 300, 3600, 21600, 86400, 604800}, // 1m, 5m, 1h, 6h, 1d, 1w
+			},
+			[]string{"provider"},
+		)
+
+		providerFetchDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_provider_fetch_duration_seconds",
+				Help:    "Duration of a provider's FetchIOCS/FetchIOCSSince call, by provider and outcome",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"provider", "outcome"},
+		)
+
+		providerLastSuccessTimestamp = promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "watchtower_provider_last_success_timestamp_seconds",
+				Help: "Unix timestamp of each provider's last successful fetch, for alerting on feed staleness",
+			},
+			[]string{"provider"},
+		)
+	})
+}
+
+// RecordPulsesFetched records pulses/entries fetched in one run.
+func RecordPulsesFetched(provider string, count int) {
+	if providerPulsesFetchedTotal != nil {
+		providerPulsesFetchedTotal.WithLabelValues(provider).Add(float64(count))
+	}
+}
+
+// RecordIndicatorsIngested records indicators successfully converted to IOCs.
+func RecordIndicatorsIngested(provider string, count int) {
+	if providerIndicatorsIngestedTotal != nil {
+		providerIndicatorsIngestedTotal.WithLabelValues(provider).Add(float64(count))
+	}
+}
+
+// RecordIndicatorSkipped records one indicator dropped for an unmapped upstream type.
+func RecordIndicatorSkipped(provider, upstreamType string) {
+	if providerIndicatorsSkippedTotal != nil {
+		providerIndicatorsSkippedTotal.WithLabelValues(provider, upstreamType).Inc()
+	}
+}
+
+// RecordIngestionLag records how old an indicator already was when it was
+// ingested. Callers should skip this for indicators with a zero FirstSeen.
+func RecordIngestionLag(provider string, lag time.Duration) {
+	if providerIngestionLagSeconds != nil {
+		providerIngestionLagSeconds.WithLabelValues(provider).Observe(lag.Seconds())
+	}
+}
+
+// RecordFetchDuration records one FetchIOCS/FetchIOCSSince call's latency.
+// outcome is "ok" or "error".
+func RecordFetchDuration(provider, outcome string, duration time.Duration) {
+	if providerFetchDuration != nil {
+		providerFetchDuration.WithLabelValues(provider, outcome).Observe(duration.Seconds())
+	}
+}
+
+// RecordFetchSuccess marks provider as having just completed a successful
+// fetch, so watchtower_provider_last_success_timestamp_seconds reflects now.
+func RecordFetchSuccess(provider string) {
+	if providerLastSuccessTimestamp != nil {
+		providerLastSuccessTimestamp.WithLabelValues(provider).SetToCurrentTime()
+	}
+}
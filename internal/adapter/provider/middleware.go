@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+)
+
+// FetchHandler performs (or continues) a provider's fetch, the unit a
+// Middleware wraps - mirroring a gRPC unary handler, but around
+// ports.ThreatProvider.FetchIOCS instead of an RPC.
+type FetchHandler func(ctx context.Context) ([]domain.IOC, error)
+
+// Middleware wraps a FetchHandler with cross-cutting behavior (recovery,
+// retry, logging, ...), in the style of gRPC unary interceptors, so the same
+// chain can be applied uniformly to every provider without each one
+// re-implementing it.
+type Middleware func(next FetchHandler) FetchHandler
+
+// Chain composes mw around handler and returns the result. The first
+// middleware given is outermost (it sees the call first and the result
+// last), matching llm.Chain's ordering.
+func Chain(handler FetchHandler, mw ...Middleware) FetchHandler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return handler
+}
+
+// ProviderError is what RecoveryMiddleware and RetryMiddleware wrap fetch
+// failures in, so callers can tell a provider-layer failure (panic,
+// exhausted retries) apart from a domain-level error returned by the
+// provider itself.
+type ProviderError struct {
+	Provider string
+	Op       string // "panic", "retry_exhausted"
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("provider %s: %s: %v", e.Provider, e.Op, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// RecoveryMiddleware recovers from panics anywhere further down the chain
+// (including in other middleware), logs the stack trace at debug level, and
+// turns the panic into a *ProviderError instead of crashing the surrounding
+// ingester. It should be installed outermost.
+func RecoveryMiddleware(providerName string) Middleware {
+	return func(next FetchHandler) FetchHandler {
+		return func(ctx context.Context) (iocs []domain.IOC, err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					slog.Debug("panic recovered in provider fetch",
+						"provider", providerName, "panic", rec, "stack", string(debug.Stack()))
+					iocs, err = nil, &ProviderError{Provider: providerName, Op: "panic", Err: fmt.Errorf("%v", rec)}
+				}
+			}()
+			return next(ctx)
+		}
+	}
+}
+
+// RetryMiddleware retries transient failures (5xx-shaped and network errors)
+// with exponential backoff and jitter, up to maxAttempts total attempts,
+// starting at baseDelay. Non-transient errors (4xx, malformed responses,
+// ...) return immediately.
+func RetryMiddleware(providerName string, maxAttempts int, baseDelay time.Duration) Middleware {
+	return func(next FetchHandler) FetchHandler {
+		return func(ctx context.Context) ([]domain.IOC, error) {
+			expBackoff := backoff.NewExponentialBackOff()
+			expBackoff.InitialInterval = baseDelay
+			expBackoff.MaxInterval = 30 * time.Second
+			expBackoff.Multiplier = 2.0
+
+			var lastErr error
+			for attempt := 0; attempt < maxAttempts; attempt++ {
+				iocs, err := next(ctx)
+				if err == nil {
+					return iocs, nil
+				}
+				if !isTransientProviderError(err) {
+					return nil, err
+				}
+				lastErr = err
+
+				if attempt == maxAttempts-1 {
+					break
+				}
+
+				wait := expBackoff.NextBackOff() + jitter(expBackoff.InitialInterval)
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				}
+			}
+
+			return nil, &ProviderError{Provider: providerName, Op: "retry_exhausted", Err: lastErr}
+		}
+	}
+}
+
+// jitter returns a random duration in [0, d), to de-synchronize retries
+// across providers hitting the same upstream at once.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// isTransientProviderError reports whether err looks like a transient
+// network or server-side failure worth retrying, as opposed to a client
+// error (4xx, malformed body) that will just fail the same way again.
+func isTransientProviderError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, code := range []string{"500", "502", "503", "504"} {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+	return strings.Contains(msg, "connection reset") || strings.Contains(msg, "eof")
+}
+
+// LoggingMiddleware replaces a provider's ad-hoc fmt.Printf progress lines
+// with structured log/slog records carrying provider, url, duration, and
+// outcome, so ingestion runs can be filtered/aggregated by field instead of
+// grepped by emoji.
+func LoggingMiddleware(providerName, feedURL string) Middleware {
+	return func(next FetchHandler) FetchHandler {
+		return func(ctx context.Context) ([]domain.IOC, error) {
+			start := time.Now()
+			slog.Info("provider fetch starting", "provider", providerName, "url", feedURL)
+
+			iocs, err := next(ctx)
+			durationMS := time.Since(start).Milliseconds()
+
+			if err != nil {
+				slog.Error("provider fetch failed",
+					"provider", providerName, "url", feedURL, "duration_ms", durationMS, "error", err)
+				return iocs, err
+			}
+
+			slog.Info("provider fetch complete",
+				"provider", providerName, "url", feedURL, "duration_ms", durationMS, "iocs_extracted", len(iocs))
+			return iocs, nil
+		}
+	}
+}
+
+// defaultRetryAttempts/defaultRetryBaseDelay bound DefaultChain's retry
+// middleware.
+const (
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = 1 * time.Second
+)
+
+// DefaultChain installs the chain every provider should run its FetchIOCS
+// through: recovery outermost (so it also catches panics raised by retry or
+// logging), then retry, then logging closest to fetch itself.
+func DefaultChain(providerName, feedURL string, fetch FetchHandler) FetchHandler {
+	return Chain(fetch,
+		RecoveryMiddleware(providerName),
+		RetryMiddleware(providerName, defaultRetryAttempts, defaultRetryBaseDelay),
+		LoggingMiddleware(providerName, feedURL))
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+)
+
+func TestChain_OrdersMiddlewareOutermostFirst(t *testing.T) {
+	var order []string
+
+	tag := func(name string) Middleware {
+		return func(next FetchHandler) FetchHandler {
+			return func(ctx context.Context) ([]domain.IOC, error) {
+				order = append(order, name)
+				return next(ctx)
+			}
+		}
+	}
+
+	base := func(ctx context.Context) ([]domain.IOC, error) {
+		order = append(order, "base")
+		return nil, nil
+	}
+
+	handler := Chain(base, tag("outer"), tag("inner"))
+	if _, err := handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"outer", "inner", "base"}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("expected order %v, got %v", want, order)
+			break
+		}
+	}
+}
+
+func TestRecoveryMiddleware_RecoversPanic(t *testing.T) {
+	handler := RecoveryMiddleware("test-provider")(func(ctx context.Context) ([]domain.IOC, error) {
+		panic("boom")
+	})
+
+	iocs, err := handler(context.Background())
+	if iocs != nil {
+		t.Errorf("expected nil iocs after recovered panic, got %+v", iocs)
+	}
+	if err == nil {
+		t.Fatal("expected an error after recovered panic")
+	}
+
+	var provErr *ProviderError
+	if !errors.As(err, &provErr) {
+		t.Fatalf("expected a *ProviderError, got %T", err)
+	}
+	if provErr.Provider != "test-provider" || provErr.Op != "panic" {
+		t.Errorf("unexpected ProviderError fields: %+v", provErr)
+	}
+}
+
+func TestRecoveryMiddleware_PassesThroughNormalResult(t *testing.T) {
+	want := []domain.IOC{{Value: "example.com", Type: domain.Domain}}
+	handler := RecoveryMiddleware("test-provider")(func(ctx context.Context) ([]domain.IOC, error) {
+		return want, nil
+	})
+
+	iocs, err := handler(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(iocs) != 1 || iocs[0].Value != "example.com" {
+		t.Errorf("expected %+v, got %+v", want, iocs)
+	}
+}
+
+func TestRetryMiddleware_RetriesTransientErrors(t *testing.T) {
+	attempts := 0
+	handler := RetryMiddleware("test-provider", 3, time.Millisecond)(func(ctx context.Context) ([]domain.IOC, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("unexpected status code: 503")
+		}
+		return []domain.IOC{{Value: "1.2.3.4", Type: domain.IPAddress}}, nil
+	})
+
+	iocs, err := handler(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if len(iocs) != 1 {
+		t.Errorf("expected 1 IOC, got %d", len(iocs))
+	}
+}
+
+func TestRetryMiddleware_DoesNotRetryNonTransientErrors(t *testing.T) {
+	attempts := 0
+	handler := RetryMiddleware("test-provider", 3, time.Millisecond)(func(ctx context.Context) ([]domain.IOC, error) {
+		attempts++
+		return nil, errors.New("unexpected status code: 404")
+	})
+
+	if _, err := handler(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt for a non-transient error, got %d", attempts)
+	}
+}
+
+func TestIsTransientProviderError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("unexpected status code: 503"), true},
+		{errors.New("connection reset by peer"), true},
+		{errors.New("unexpected status code: 404"), false},
+		{errors.New("failed to decode json"), false},
+	}
+
+	for _, tt := range tests {
+		if got := isTransientProviderError(tt.err); got != tt.want {
+			t.Errorf("isTransientProviderError(%q) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+)
+
+// MISPProviderConfig configures a MISPProvider's source, which is either the
+// static MISP "feed" format or a live instance's restSearch API - exactly
+// one of FeedBaseURL or InstanceURL+APIKey should be set.
+type MISPProviderConfig struct {
+	// SourceName identifies this feed in domain.IOC.Source, e.g. "misp-circl".
+	SourceName string
+
+	// FeedBaseURL, when set, polls the static MISP feed format: a
+	// manifest.json listing event UUID -> metadata, plus one "<uuid>.json"
+	// per event, both served from this base URL.
+	FeedBaseURL string
+
+	// InstanceURL/APIKey, when both set, poll a live MISP instance's
+	// /events/restSearch endpoint instead of a static feed.
+	InstanceURL string
+	APIKey      string
+}
+
+// MISPProvider implements ports.ThreatProvider (and
+// ports.IncrementalThreatProvider) by ingesting MISP events - either from a
+// static feed's manifest.json/per-event JSON files, or from a live
+// instance's restSearch API - and converting to_ids attributes into
+// domain.IOC.
+type MISPProvider struct {
+	client *http.Client
+	config MISPProviderConfig
+}
+
+// NewMISPProvider builds a MISPProvider. When client is nil, http.DefaultClient is used.
+func NewMISPProvider(client *http.Client, config MISPProviderConfig) *MISPProvider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &MISPProvider{client: client, config: config}
+}
+
+func (p *MISPProvider) Name() string {
+	if p.config.SourceName != "" {
+		return p.config.SourceName
+	}
+	return "misp"
+}
+
+// mispManifestEntry is one event's metadata in a MISP feed's manifest.json,
+// keyed by event UUID.
+type mispManifestEntry struct {
+	Timestamp string `json:"timestamp"`
+}
+
+// mispEventEnvelope wraps a MISP event the way both the feed's per-event
+// JSON files and a live instance's restSearch response do.
+type mispEventEnvelope struct {
+	Event mispEvent `json:"Event"`
+}
+
+type mispEvent struct {
+	UUID          string          `json:"uuid"`
+	Info          string          `json:"info"`
+	ThreatLevelID string          `json:"threat_level_id"`
+	Timestamp     string          `json:"timestamp"`
+	Tag           []mispTag       `json:"Tag"`
+	Attribute     []mispAttribute `json:"Attribute"`
+}
+
+type mispTag struct {
+	Name string `json:"name"`
+}
+
+type mispAttribute struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	ToIDS     bool   `json:"to_ids"`
+	Timestamp string `json:"timestamp"`
+}
+
+// mispSearchResponse is the body of a live instance's
+// /events/restSearch response.
+type mispSearchResponse struct {
+	Response []mispEventEnvelope `json:"response"`
+}
+
+func (p *MISPProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
+	iocs, _, err := p.FetchIOCSSince(ctx, time.Time{})
+	return iocs, err
+}
+
+// FetchIOCSSince implements ports.IncrementalThreatProvider: against a feed
+// it only downloads event files whose manifest timestamp is newer than
+// since, and against a live instance it passes since as the restSearch
+// "timestamp" filter. Either way it returns the newest event timestamp seen
+// as the next watermark.
+func (p *MISPProvider) FetchIOCSSince(ctx context.Context, since time.Time) ([]domain.IOC, time.Time, error) {
+	if p.config.InstanceURL != "" && p.config.APIKey != "" {
+		return p.fetchFromAPI(ctx, since)
+	}
+	if p.config.FeedBaseURL != "" {
+		return p.fetchFromFeed(ctx, since)
+	}
+	return nil, time.Time{}, fmt.Errorf("misp: neither FeedBaseURL nor InstanceURL+APIKey configured")
+}
+
+// fetchFromFeed pulls the static MISP feed format: manifest.json lists every
+// event's UUID and last-modified timestamp, so we only fetch "<uuid>.json"
+// for events that changed since the last sync.
+func (p *MISPProvider) fetchFromFeed(ctx context.Context, since time.Time) ([]domain.IOC, time.Time, error) {
+	manifest, err := p.fetchManifest(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var iocs []domain.IOC
+	latestModified := since
+
+	for uuid, entry := range manifest {
+		eventTimestamp := parseMISPTimestamp(entry.Timestamp)
+		if !since.IsZero() && !eventTimestamp.After(since) {
+			continue
+		}
+
+		event, err := p.fetchFeedEvent(ctx, uuid)
+		if err != nil {
+			log.Printf("⚠️  misp: failed to fetch feed event %s: %v", uuid, err)
+			continue
+		}
+
+		if eventTimestamp.After(latestModified) {
+			latestModified = eventTimestamp
+		}
+		iocs = append(iocs, p.convertEvent(event)...)
+	}
+
+	return iocs, latestModified, nil
+}
+
+func (p *MISPProvider) fetchManifest(ctx context.Context) (map[string]mispManifestEntry, error) {
+	manifestURL := strings.TrimSuffix(p.config.FeedBaseURL, "/") + "/manifest.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, manifestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build MISP manifest request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch MISP manifest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected MISP manifest status code: %d", resp.StatusCode)
+	}
+
+	var manifest map[string]mispManifestEntry
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode MISP manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func (p *MISPProvider) fetchFeedEvent(ctx context.Context, uuid string) (mispEvent, error) {
+	eventURL := strings.TrimSuffix(p.config.FeedBaseURL, "/") + "/" + uuid + ".json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, eventURL, nil)
+	if err != nil {
+		return mispEvent{}, fmt.Errorf("failed to build MISP event request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return mispEvent{}, fmt.Errorf("failed to fetch MISP event %s: %w", uuid, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return mispEvent{}, fmt.Errorf("unexpected MISP event status code: %d", resp.StatusCode)
+	}
+
+	var envelope mispEventEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return mispEvent{}, fmt.Errorf("failed to decode MISP event %s: %w", uuid, err)
+	}
+	return envelope.Event, nil
+}
+
+// fetchFromAPI pulls events from a live MISP instance's restSearch endpoint,
+// filtering server-side by since when set.
+func (p *MISPProvider) fetchFromAPI(ctx context.Context, since time.Time) ([]domain.IOC, time.Time, error) {
+	body := map[string]string{"returnFormat": "json"}
+	if !since.IsZero() {
+		body["timestamp"] = strconv.FormatInt(since.Unix(), 10)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to encode MISP restSearch request: %w", err)
+	}
+
+	searchURL := strings.TrimSuffix(p.config.InstanceURL, "/") + "/events/restSearch"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, searchURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to build MISP restSearch request: %w", err)
+	}
+	req.Header.Set("Authorization", p.config.APIKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to call MISP restSearch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("unexpected MISP restSearch status code: %d", resp.StatusCode)
+	}
+
+	var result mispSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to decode MISP restSearch response: %w", err)
+	}
+
+	var iocs []domain.IOC
+	latestModified := since
+	for _, wrapper := range result.Response {
+		eventTimestamp := parseMISPTimestamp(wrapper.Event.Timestamp)
+		if eventTimestamp.After(latestModified) {
+			latestModified = eventTimestamp
+		}
+		iocs = append(iocs, p.convertEvent(wrapper.Event)...)
+	}
+
+	return iocs, latestModified, nil
+}
+
+// convertEvent maps a MISP event's to_ids attributes to domain.IOC, skipping
+// attributes not flagged to_ids since those aren't meant to drive detection.
+// URL attributes are run through domain.ExtractIOCComponents so their
+// embedded IP/domain sub-components get indexed too.
+func (p *MISPProvider) convertEvent(event mispEvent) []domain.IOC {
+	threatType := mispThreatLevel(event.ThreatLevelID)
+
+	tags := make([]string, 0, len(event.Tag))
+	for _, tag := range event.Tag {
+		if tag.Name != "" {
+			tags = append(tags, tag.Name)
+		}
+	}
+
+	eventTimestamp := parseMISPTimestamp(event.Timestamp)
+
+	var iocs []domain.IOC
+	for _, attr := range event.Attribute {
+		if !attr.ToIDS {
+			continue
+		}
+
+		iocType, ok := mapMISPType(attr.Type)
+		if !ok {
+			continue
+		}
+
+		firstSeen := parseMISPTimestamp(attr.Timestamp)
+		if firstSeen.IsZero() {
+			firstSeen = eventTimestamp
+		}
+
+		ioc := domain.IOC{
+			Value:        attr.Value,
+			Type:         iocType,
+			Source:       p.Name(),
+			ThreatType:   threatType,
+			Tags:         tags,
+			FirstSeen:    firstSeen,
+			DateIngested: time.Now(),
+		}
+
+		if iocType == domain.URL {
+			iocs = append(iocs, domain.ExtractIOCComponents(attr.Value, ioc)...)
+		} else {
+			iocs = append(iocs, ioc)
+		}
+	}
+	return iocs
+}
+
+// mapMISPType maps a MISP attribute type to a domain.IOCType, returning
+// ok=false for types this provider doesn't convert.
+func mapMISPType(attrType string) (domain.IOCType, bool) {
+	switch attrType {
+	case "ip-src", "ip-dst":
+		return domain.IPAddress, true
+	case "domain", "hostname":
+		return domain.Domain, true
+	case "url":
+		return domain.URL, true
+	case "md5", "sha1", "sha256":
+		return domain.FileHash, true
+	default:
+		return "", false
+	}
+}
+
+// mispThreatLevel maps MISP's threat_level_id (1=High, 2=Medium, 3=Low,
+// 4=Undefined) to the lowercase ThreatType strings the rest of this codebase uses.
+func mispThreatLevel(id string) string {
+	switch id {
+	case "1":
+		return "high"
+	case "2":
+		return "medium"
+	case "3":
+		return "low"
+	default:
+		return "undefined"
+	}
+}
+
+// parseMISPTimestamp parses a MISP timestamp field, which is a Unix epoch
+// in seconds encoded as a string.
+func parseMISPTimestamp(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	secs, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0).UTC()
+}
@@ -59,6 +59,15 @@ type osvEntry struct {
 }
 
 func (p *OSVProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
+	iocs, _, err := p.FetchIOCSSince(ctx, time.Time{})
+	return iocs, err
+}
+
+// FetchIOCSSince implements ports.IncrementalThreatProvider: it still
+// downloads the full all.zip dump (OSV doesn't expose a delta API), but skips
+// any entry whose Modified timestamp is not after since, so callers avoid
+// re-deriving IOCs for packages that haven't changed since the last sync.
+func (p *OSVProvider) FetchIOCSSince(ctx context.Context, since time.Time) ([]domain.IOC, time.Time, error) {
 	// 1. Construir URL baseada no ecossistema
 	url := fmt.Sprintf("%s/%s/all.zip", osvBaseURL, p.ecosystem)
 
@@ -66,22 +75,23 @@ func (p *OSVProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 	defer resp.Body.Close()
 
 	// Ler tudo para memória (o zip não é gigante, ~alguns MBs)
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	zipReader, err := zip.NewReader(bytes.NewReader(bodyBytes), int64(len(bodyBytes)))
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	var iocs []domain.IOC
+	latestModified := since
 
 	// 2. Ler cada JSON dentro do ZIP
 	for _, file := range zipReader.File {
@@ -96,6 +106,17 @@ func (p *OSVProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 
 		var entry osvEntry
 		if err := json.NewDecoder(rc).Decode(&entry); err == nil {
+			if entry.Modified.After(latestModified) {
+				latestModified = entry.Modified
+			}
+
+			if !since.IsZero() && !entry.Modified.After(since) {
+				if err := rc.Close(); err != nil {
+					log.Printf("Warning: failed to close zip entry: %v", err)
+				}
+				continue
+			}
+
 			// Extrair nomes de pacotes e versões afetadas
 			for _, affected := range entry.Affected {
 				pkgName := affected.Package.Name
@@ -140,5 +161,5 @@ func (p *OSVProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 		}
 	}
 
-	return iocs, nil
+	return iocs, latestModified, nil
 }
@@ -4,17 +4,42 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/hive-corporation/watchtower/internal/core/domain"
 )
 
-const otxURL = "https://otx.alienvault.com/api/v1/pulses/subscribed?limit=10&modified_since=7d"
+const (
+	otxURL = "https://otx.alienvault.com/api/v1/pulses/subscribed"
+
+	// otxMaxPages bounds how many pages of Next we'll follow in one run, so a
+	// misbehaving or very active subscription can't turn one ingest run into
+	// an unbounded crawl.
+	otxMaxPages = 50
+
+	// otxPageTimeout caps each individual page request independently of the
+	// caller's overall context deadline.
+	otxPageTimeout = 30 * time.Second
+
+	// otxDefaultWindow is the look-back OTX uses when we have no prior
+	// watermark (first run for this provider).
+	otxDefaultWindow = "7d"
+
+	otxMaxAttempts = 5
+)
 
 type OTXProvider struct {
 	client *http.Client
 	apiKey string
+
+	mu        sync.Mutex
+	etagCache map[string]string // page URL -> last seen ETag, for conditional requests
 }
 
 func NewOTXProvider(client *http.Client, apiKey string) *OTXProvider {
@@ -22,8 +47,9 @@ func NewOTXProvider(client *http.Client, apiKey string) *OTXProvider {
 		client = http.DefaultClient
 	}
 	return &OTXProvider{
-		client: client,
-		apiKey: apiKey,
+		client:    client,
+		apiKey:    apiKey,
+		etagCache: make(map[string]string),
 	}
 }
 
@@ -52,66 +78,202 @@ type otxIndicator struct {
 	Created   string `json:"created"`
 }
 
+// FetchIOCS implements ports.ThreatProvider with a full, non-incremental
+// sweep (OTX's default look-back window).
 func (p *OTXProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
+	iocs, _, err := p.FetchIOCSSince(ctx, time.Time{})
+	return iocs, err
+}
+
+// FetchIOCSSince implements ports.IncrementalThreatProvider: it follows
+// otxResponse.Next until OTX stops paginating (bounded by otxMaxPages), maps
+// every indicator type the request asked us to stop dropping, and reports
+// the latest indicator timestamp seen so the caller can persist it as the
+// next run's watermark.
+func (p *OTXProvider) FetchIOCSSince(ctx context.Context, since time.Time) ([]domain.IOC, time.Time, error) {
 	if p.apiKey == "" {
-		return nil, fmt.Errorf("OTX API Key is missing")
+		return nil, time.Time{}, fmt.Errorf("OTX API Key is missing")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", otxURL, nil)
-	if err != nil {
-		return nil, err
+	modifiedSince := otxDefaultWindow
+	if !since.IsZero() {
+		modifiedSince = since.UTC().Format(time.RFC3339)
+	}
+	nextURL := fmt.Sprintf("%s?limit=50&modified_since=%s", otxURL, url.QueryEscape(modifiedSince))
+
+	var iocs []domain.IOC
+	latestModified := since
+	pulsesFetched := 0
+	indicatorsIngested := 0
+
+	page := 0
+	for nextURL != "" {
+		if page >= otxMaxPages {
+			log.Printf("⚠️ OTX pagination guard hit after %d pages, stopping early with more pages remaining", page)
+			break
+		}
+		page++
+
+		pageCtx, cancel := context.WithTimeout(ctx, otxPageTimeout)
+		data, notModified, err := p.fetchPage(pageCtx, nextURL)
+		cancel()
+		if err != nil {
+			return iocs, latestModified, err
+		}
+		if notModified {
+			// Unchanged since our last conditional request: nothing further
+			// in this subscription to decode, and no further pages to chase.
+			break
+		}
+
+		pulsesFetched += len(data.Results)
+		for _, pulse := range data.Results {
+			for _, ind := range pulse.Indicators {
+				myType := mapOTXType(ind.Type)
+				if myType == "" {
+					RecordIndicatorSkipped(p.Name(), ind.Type)
+					continue
+				}
+
+				firstSeen, _ := time.Parse(time.RFC3339, ind.Created)
+				if firstSeen.IsZero() {
+					firstSeen = time.Now()
+				}
+				if firstSeen.After(latestModified) {
+					latestModified = firstSeen
+				}
+
+				iocs = append(iocs, domain.IOC{
+					Value:        ind.Indicator,
+					Type:         myType,
+					Source:       p.Name(),
+					ThreatType:   pulse.Name, // Usamos o nome do Pulso como "Ameaça"
+					Tags:         pulse.Tags,
+					FirstSeen:    firstSeen,
+					DateIngested: time.Now(),
+				})
+				indicatorsIngested++
+			}
+		}
+
+		nextURL = data.Next
 	}
 
-	// OTX exige a Key no Header
-	req.Header.Set("X-OTX-API-KEY", p.apiKey)
+	RecordPulsesFetched(p.Name(), pulsesFetched)
+	RecordIndicatorsIngested(p.Name(), indicatorsIngested)
 
-	resp, err := p.client.Do(req)
+	return iocs, latestModified, nil
+}
+
+// fetchPage requests pageURL, sending an If-None-Match for any ETag we
+// cached from a previous call to the same URL. notModified reports a 304,
+// which means the caller should stop decoding (and, since OTX pages are
+// stable once fetched, stop paginating).
+func (p *OTXProvider) fetchPage(ctx context.Context, pageURL string) (otxResponse, bool, error) {
+	p.mu.Lock()
+	cachedETag := p.etagCache[pageURL]
+	p.mu.Unlock()
+
+	resp, err := p.doRequest(ctx, pageURL, cachedETag)
 	if err != nil {
-		return nil, err
+		return otxResponse{}, false, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return otxResponse{}, true, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OTX API error: status %d", resp.StatusCode)
+		return otxResponse{}, false, fmt.Errorf("OTX API error: status %d", resp.StatusCode)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		p.mu.Lock()
+		p.etagCache[pageURL] = etag
+		p.mu.Unlock()
 	}
 
 	var data otxResponse
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode OTX json: %w", err)
+		return otxResponse{}, false, fmt.Errorf("failed to decode OTX json: %w", err)
 	}
+	return data, false, nil
+}
 
-	var iocs []domain.IOC
+// doRequest executes one OTX request, retrying with exponential backoff on
+// 429/5xx. A Retry-After header on the failing response overrides the
+// computed backoff interval, since OTX knows its own rate-limit window
+// better than we do.
+func (p *OTXProvider) doRequest(ctx context.Context, pageURL, etag string) (*http.Response, error) {
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = 1 * time.Second
+	expBackoff.MaxInterval = 30 * time.Second
+	expBackoff.Multiplier = 2.0
 
-	// Itera sobre os Pulsos
-	for _, pulse := range data.Results {
-		// Itera sobre os Indicadores dentro do Pulso
-		for _, ind := range pulse.Indicators {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		// OTX exige a Key no Header
+		req.Header.Set("X-OTX-API-KEY", p.apiKey)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
 
-			// Converte o Tipo do OTX para o Nosso Tipo
-			myType := mapOTXType(ind.Type)
-			if myType == "" {
-				continue // Ignora tipos que não sabemos lidar (ex: email, cve)
+		resp, err := p.client.Do(req)
+		if err != nil {
+			if attempt >= otxMaxAttempts-1 {
+				return nil, fmt.Errorf("OTX request failed after %d attempts: %w", attempt+1, err)
 			}
-
-			// Parsing de datas (OTX usa ISO8601 variada, simplificando aqui)
-			firstSeen, _ := time.Parse(time.RFC3339, ind.Created)
-			if firstSeen.IsZero() {
-				firstSeen = time.Now()
+			if !sleepCtx(ctx, expBackoff.NextBackOff()) {
+				return nil, ctx.Err()
 			}
+			continue
+		}
 
-			iocs = append(iocs, domain.IOC{
-				Value:        ind.Indicator,
-				Type:         myType,
-				Source:       p.Name(),
-				ThreatType:   pulse.Name, // Usamos o nome do Pulso como "Ameaça"
-				Tags:         pulse.Tags,
-				FirstSeen:    firstSeen,
-				DateIngested: time.Now(),
-			})
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			wait := retryAfterDuration(resp, expBackoff.NextBackOff())
+			resp.Body.Close()
+			if attempt >= otxMaxAttempts-1 {
+				return nil, fmt.Errorf("OTX API error: status %d after %d attempts", resp.StatusCode, attempt+1)
+			}
+			if !sleepCtx(ctx, wait) {
+				return nil, ctx.Err()
+			}
+			continue
 		}
+
+		return resp, nil
 	}
+}
 
-	return iocs, nil
+// retryAfterDuration reads a Retry-After header (either delay-seconds or an
+// HTTP-date), falling back to fallback when absent or unparseable.
+func retryAfterDuration(resp *http.Response, fallback time.Duration) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return fallback
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return fallback
+}
+
+// sleepCtx waits for d, returning false early if ctx is cancelled first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
 func mapOTXType(otxType string) domain.IOCType {
@@ -120,10 +282,22 @@ func mapOTXType(otxType string) domain.IOCType {
 		return domain.IPAddress
 	case "domain", "hostname":
 		return domain.Domain
-	case "url":
+	case "url", "URL", "URI":
 		return domain.URL
 	case "FileHash-MD5", "FileHash-SHA1", "FileHash-SHA256":
 		return domain.FileHash
+	case "email":
+		return domain.Email
+	case "CVE":
+		return domain.CVE
+	case "YARA":
+		return domain.YARA
+	case "Mutex":
+		return domain.Mutex
+	case "FileName":
+		return domain.FileName
+	case "FilePath":
+		return domain.FilePath
 	default:
 		return ""
 	}
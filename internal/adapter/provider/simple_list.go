@@ -32,8 +32,12 @@ func (p *SimpleListProvider) Name() string {
 }
 
 func (p *SimpleListProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
-	fmt.Printf("DEBUG: Fetching %s from %s\n", p.providerName, p.url)
+	return DefaultChain(p.providerName, p.url, p.fetchIOCS)(ctx)
+}
 
+// fetchIOCS does the actual download and parsing; FetchIOCS runs it through
+// DefaultChain for recovery, retry, and structured logging.
+func (p *SimpleListProvider) fetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
 	if err != nil {
 		return nil, err
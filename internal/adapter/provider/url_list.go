@@ -4,14 +4,29 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hive-corporation/watchtower/internal/core/domain"
 )
 
+// streamScannerMaxTokenSize raises bufio.Scanner's default 64 KiB line limit,
+// which feeds like URLhaus have been observed to exceed and silently truncate.
+const streamScannerMaxTokenSize = 1 << 20 // 1 MiB
+
+// streamWorkerCount is the number of goroutines parsing scanned lines
+// concurrently in FetchIOCStream.
+const streamWorkerCount = 4
+
+// streamChannelBuffer bounds how far the producer/workers/consumer stages of
+// FetchIOCStream can run ahead of each other.
+const streamChannelBuffer = 256
+
 // URLListProvider fetches lists of URLs and automatically extracts IP/domain components
 // This solves the problem where searching for "198.0.2.12" wouldn't match "http://198.0.2.12/malware.sh"
 type URLListProvider struct {
@@ -35,8 +50,12 @@ func (p *URLListProvider) Name() string {
 }
 
 func (p *URLListProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
-	fmt.Printf("📥 Fetching %s from %s (with component extraction)\n", p.providerName, p.url)
+	return DefaultChain(p.providerName, p.url, p.fetchIOCS)(ctx)
+}
 
+// fetchIOCS does the actual download and parsing; FetchIOCS runs it through
+// DefaultChain for recovery, retry, and structured logging.
+func (p *URLListProvider) fetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
 	if err != nil {
 		return nil, err
@@ -54,89 +73,180 @@ func (p *URLListProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 
 	var iocs []domain.IOC
 	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), streamScannerMaxTokenSize)
 	lineCount := 0
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
 		lineCount++
+		iocs = append(iocs, p.parseLine(scanner.Text())...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner error: %w", err)
+	}
+
+	slog.Info("provider parse complete",
+		"provider", p.providerName, "lines", lineCount, "iocs_extracted", len(iocs))
+
+	return iocs, nil
+}
+
+// parseLine turns a single scanned feed line into zero or more IOCs (zero for
+// blank/comment lines, more than one when the line is a URL that
+// domain.ExtractIOCComponents also breaks into its host/IP). Shared by
+// fetchIOCS and FetchIOCStream so both parse identically.
+func (p *URLListProvider) parseLine(raw string) []domain.IOC {
+	line := strings.TrimSpace(raw)
+
+	// Skip empty lines and comments
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+		return nil
+	}
+
+	// Remove inline comments
+	if idx := strings.Index(line, "#"); idx != -1 {
+		line = strings.TrimSpace(line[:idx])
+	}
+
+	if line == "" {
+		return nil
+	}
+
+	now := time.Now()
+
+	// Detect if it's a URL or plain IP/domain
+	if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+		// It's a URL - extract components
+		baseIOC := domain.IOC{
+			Value:        line,
+			Type:         domain.URL,
+			Source:       p.providerName,
+			ThreatType:   p.threatType,
+			Tags:         []string{"malware-url", "threat-feed"},
+			Version:      "",
+			FirstSeen:    now,
+			DateIngested: now,
+		}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
-			continue
+		// Extract all components (URL + IP/domain)
+		return domain.ExtractIOCComponents(line, baseIOC)
+	}
+
+	if parsedURL, err := url.Parse("http://" + line); err == nil && parsedURL.Host != "" {
+		// Might be a domain or IP without protocol
+		// Try adding http:// prefix
+		fullURL := "http://" + line
+
+		baseIOC := domain.IOC{
+			Value:        fullURL,
+			Type:         domain.URL,
+			Source:       p.providerName,
+			ThreatType:   p.threatType,
+			Tags:         []string{"malware-url", "threat-feed"},
+			Version:      "",
+			FirstSeen:    now,
+			DateIngested: now,
 		}
 
-		// Remove inline comments
-		if idx := strings.Index(line, "#"); idx != -1 {
-			line = strings.TrimSpace(line[:idx])
+		return domain.ExtractIOCComponents(fullURL, baseIOC)
+	}
+
+	// Plain value (could be IP, domain, or hash)
+	iocType := detectIOCType(line)
+	return []domain.IOC{{
+		Value:        line,
+		Type:         iocType,
+		Source:       p.providerName,
+		ThreatType:   p.threatType,
+		Tags:         []string{"threat-feed"},
+		Version:      "",
+		FirstSeen:    now,
+		DateIngested: now,
+	}}
+}
+
+// FetchIOCStream parses the feed incrementally instead of materializing every
+// IOC into a slice before returning, so peak memory stays flat regardless of
+// feed size: a producer goroutine scans lines (with a raised max token size,
+// see streamScannerMaxTokenSize), streamWorkerCount worker goroutines parse
+// them concurrently via parseLine, and their output fans in to the returned
+// channel for the caller to batch and persist as it arrives. The error
+// channel carries at most one terminal error and is closed alongside the IOC
+// channel once the feed has been fully read (or ctx is done).
+func (p *URLListProvider) FetchIOCStream(ctx context.Context) (<-chan domain.IOC, <-chan error) {
+	out := make(chan domain.IOC, streamChannelBuffer)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errc)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+		if err != nil {
+			errc <- err
+			return
 		}
 
-		if line == "" {
-			continue
+		resp, err := p.client.Do(req)
+		if err != nil {
+			errc <- err
+			return
 		}
+		defer resp.Body.Close()
 
-		now := time.Now()
-
-		// Detect if it's a URL or plain IP/domain
-		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
-			// It's a URL - extract components
-			baseIOC := domain.IOC{
-				Value:        line,
-				Type:         domain.URL,
-				Source:       p.providerName,
-				ThreatType:   p.threatType,
-				Tags:         []string{"malware-url", "threat-feed"},
-				Version:      "",
-				FirstSeen:    now,
-				DateIngested: now,
-			}
+		if resp.StatusCode != http.StatusOK {
+			errc <- fmt.Errorf("failed to fetch IOCs from %s: %s", p.url, resp.Status)
+			return
+		}
 
-			// Extract all components (URL + IP/domain)
-			components := domain.ExtractIOCComponents(line, baseIOC)
-			iocs = append(iocs, components...)
-
-		} else if parsedURL, err := url.Parse("http://" + line); err == nil && parsedURL.Host != "" {
-			// Might be a domain or IP without protocol
-			// Try adding http:// prefix
-			fullURL := "http://" + line
-
-			baseIOC := domain.IOC{
-				Value:        fullURL,
-				Type:         domain.URL,
-				Source:       p.providerName,
-				ThreatType:   p.threatType,
-				Tags:         []string{"malware-url", "threat-feed"},
-				Version:      "",
-				FirstSeen:    now,
-				DateIngested: now,
-			}
+		lines := make(chan string, streamChannelBuffer)
+		var workers sync.WaitGroup
+		workers.Add(streamWorkerCount)
+		for i := 0; i < streamWorkerCount; i++ {
+			go func() {
+				defer workers.Done()
+				for line := range lines {
+					for _, ioc := range p.parseLine(line) {
+						select {
+						case out <- ioc:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}()
+		}
+
+		var lineCount int64
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), streamScannerMaxTokenSize)
 
-			components := domain.ExtractIOCComponents(fullURL, baseIOC)
-			iocs = append(iocs, components...)
-
-		} else {
-			// Plain value (could be IP, domain, or hash)
-			iocType := detectIOCType(line)
-			iocs = append(iocs, domain.IOC{
-				Value:        line,
-				Type:         iocType,
-				Source:       p.providerName,
-				ThreatType:   p.threatType,
-				Tags:         []string{"threat-feed"},
-				Version:      "",
-				FirstSeen:    now,
-				DateIngested: now,
-			})
+	scanLoop:
+		for scanner.Scan() {
+			atomic.AddInt64(&lineCount, 1)
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				break scanLoop
+			}
 		}
-	}
+		close(lines)
+		workers.Wait()
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scanner error: %w", err)
-	}
+		if err := ctx.Err(); err != nil {
+			errc <- err
+			return
+		}
+		if err := scanner.Err(); err != nil {
+			errc <- fmt.Errorf("scanner error: %w", err)
+			return
+		}
 
-	fmt.Printf("✅ %s: Parsed %d lines, extracted %d IOCs (including components)\n",
-		p.providerName, lineCount, len(iocs))
+		slog.Info("provider stream complete",
+			"provider", p.providerName, "lines", atomic.LoadInt64(&lineCount))
+	}()
 
-	return iocs, nil
+	return out, errc
 }
 
 // detectIOCType attempts to determine IOC type from the value
@@ -32,6 +32,12 @@ func (p *URLHausProvider) Name() string {
 }
 
 func (p *URLHausProvider) FetchIOCS(ctx context.Context) ([]domain.IOC, error) {
+	return DefaultChain(p.Name(), urlHausCSV, p.fetchIOCS)(ctx)
+}
+
+// fetchIOCS does the actual download and parsing; FetchIOCS runs it through
+// DefaultChain for recovery, retry, and structured logging.
+func (p *URLHausProvider) fetchIOCS(ctx context.Context) ([]domain.IOC, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", urlHausCSV, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// metricsOnce ensures metrics are registered only once
+	metricsOnce sync.Once
+
+	// batchSaveDuration tracks PostgresRepository.SaveBatch latency
+	// (insert batch plus confidence recompute), by outcome.
+	batchSaveDuration *prometheus.HistogramVec
+
+	// batchSaveSize tracks how many IOCs were passed to a single SaveBatch call.
+	batchSaveSize prometheus.Histogram
+)
+
+// InitMetrics registers all Prometheus metrics for the repository package.
+// This should be called once at application startup.
+func InitMetrics() {
+	metricsOnce.Do(func() {
+		batchSaveDuration = promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_repository_batch_save_duration_seconds",
+				Help:    "Duration of PostgresRepository.SaveBatch, by outcome (ok, error)",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"outcome"},
+		)
+
+		batchSaveSize = promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "watchtower_repository_batch_save_size",
+				Help:    "Number of IOCs passed to a single SaveBatch call",
+				Buckets: prometheus.ExponentialBuckets(1, 4, 8), // 1..16384
+			},
+		)
+	})
+}
+
+// RecordBatchSave records one SaveBatch call's size and latency.
+func RecordBatchSave(size int, outcome string, duration time.Duration) {
+	if batchSaveDuration != nil {
+		batchSaveDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+	}
+	if batchSaveSize != nil {
+		batchSaveSize.Observe(float64(size))
+	}
+}
@@ -11,14 +11,24 @@ import (
 )
 
 type PostgresRepository struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	scoring domain.ScoringConfig
 }
 
 func NewPostgresRepository(db *pgxpool.Pool) *PostgresRepository {
-	return &PostgresRepository{db: db}
+	return &PostgresRepository{db: db, scoring: domain.DefaultScoringConfig()}
 }
 
-func (r *PostgresRepository) SaveBatch(ctx context.Context, iocs []domain.IOC) error {
+func (r *PostgresRepository) SaveBatch(ctx context.Context, iocs []domain.IOC) (err error) {
+	start := time.Now()
+	defer func() {
+		outcome := "ok"
+		if err != nil {
+			outcome = "error"
+		}
+		RecordBatchSave(len(iocs), outcome, time.Since(start))
+	}()
+
 	batch := &pgx.Batch{}
 
 	query := `
@@ -41,19 +51,106 @@ func (r *PostgresRepository) SaveBatch(ctx context.Context, iocs []domain.IOC) e
 	}
 
 	br := r.db.SendBatch(ctx, batch)
-	defer br.Close()
-
-	_, err := br.Exec()
+	_, err = br.Exec()
+	br.Close()
 	if err != nil {
 		return fmt.Errorf("failed to execute batch: %w", err)
 	}
 
+	if err := r.recomputeConfidence(ctx, distinctValues(iocs)); err != nil {
+		return fmt.Errorf("failed to recompute confidence scores: %w", err)
+	}
+
 	return nil
 }
 
+// recomputeConfidence refreshes the stored Confidence/LastSeen for values,
+// so a fresh sighting from any provider immediately moves the score. It's
+// called from both SaveBatch (for the values a batch touched) and
+// RecomputeStaleConfidence (for values a batch hasn't touched in a while),
+// so a long-silent value still keeps decaying toward zero either way.
+func (r *PostgresRepository) recomputeConfidence(ctx context.Context, values []string) error {
+	for _, value := range values {
+		sightings, err := r.FindAllByValue(ctx, value)
+		if err != nil {
+			return fmt.Errorf("failed to load sightings for %q: %w", value, err)
+		}
+		if len(sightings) == 0 {
+			continue
+		}
+
+		score, _ := domain.CalculateConfidenceScore(sightings, r.scoring)
+		lastSeen := latestDateIngested(sightings)
+
+		if _, err := r.db.Exec(ctx, `UPDATE iocs SET confidence = $1, last_seen = $2 WHERE value = $3`, score, lastSeen, value); err != nil {
+			return fmt.Errorf("failed to update confidence for %q: %w", value, err)
+		}
+	}
+
+	return nil
+}
+
+// RecomputeStaleConfidence re-runs recomputeConfidence for every IOC value
+// whose last_seen (or date_ingested, for rows predating that column) is
+// older than olderThan. SaveBatch only recomputes a value's score when a
+// source reports a fresh sighting of it, so a value that goes quiet would
+// otherwise keep its peak Confidence forever; intended to be run on a
+// schedule (see cmd/ingester) so recencyDecay's time-since-DateIngested
+// term actually drives the stored score down between sightings.
+func (r *PostgresRepository) RecomputeStaleConfidence(ctx context.Context, olderThan time.Duration) error {
+	cutoff := time.Now().Add(-olderThan)
+
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT value FROM iocs WHERE COALESCE(last_seen, date_ingested) < $1`, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to list stale IOC values: %w", err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return fmt.Errorf("failed to scan stale IOC value: %w", err)
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate stale IOC values: %w", err)
+	}
+
+	return r.recomputeConfidence(ctx, values)
+}
+
+// distinctValues returns the unique ioc.Value entries in iocs, preserving
+// first-seen order, so SaveBatch only recomputes each value's score once
+// even when a batch carries multiple sightings of it.
+func distinctValues(iocs []domain.IOC) []string {
+	seen := make(map[string]bool, len(iocs))
+	values := make([]string, 0, len(iocs))
+	for _, ioc := range iocs {
+		if !seen[ioc.Value] {
+			seen[ioc.Value] = true
+			values = append(values, ioc.Value)
+		}
+	}
+	return values
+}
+
+// latestDateIngested returns the most recent DateIngested across iocs, for
+// populating LastSeen.
+func latestDateIngested(iocs []domain.IOC) time.Time {
+	var latest time.Time
+	for _, ioc := range iocs {
+		if ioc.DateIngested.After(latest) {
+			latest = ioc.DateIngested
+		}
+	}
+	return latest
+}
+
 func (r *PostgresRepository) FindByValue(ctx context.Context, value string) (*domain.IOC, error) {
 	query := `
-		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested, confidence, COALESCE(last_seen, date_ingested)
 		FROM iocs
 		WHERE value = $1
 		LIMIT 1
@@ -70,6 +167,8 @@ func (r *PostgresRepository) FindByValue(ctx context.Context, value string) (*do
 		&ioc.Version,
 		&ioc.FirstSeen,
 		&ioc.DateIngested,
+		&ioc.Confidence,
+		&ioc.LastSeen,
 	)
 
 	if err != nil {
@@ -81,7 +180,7 @@ func (r *PostgresRepository) FindByValue(ctx context.Context, value string) (*do
 
 func (r *PostgresRepository) FindAllByValue(ctx context.Context, value string) ([]domain.IOC, error) {
 	query := `
-		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested, confidence, COALESCE(last_seen, date_ingested)
 		FROM iocs
 		WHERE value = $1
 		ORDER BY date_ingested DESC
@@ -106,6 +205,8 @@ func (r *PostgresRepository) FindAllByValue(ctx context.Context, value string) (
 			&ioc.Version,
 			&ioc.FirstSeen,
 			&ioc.DateIngested,
+			&ioc.Confidence,
+			&ioc.LastSeen,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan IOC: %w", err)
@@ -122,7 +223,7 @@ func (r *PostgresRepository) FindAllByValue(ctx context.Context, value string) (
 
 func (r *PostgresRepository) FindByValueAndVersion(ctx context.Context, value, version string) ([]domain.IOC, error) {
 	query := `
-		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested, confidence, COALESCE(last_seen, date_ingested)
 		FROM iocs
 		WHERE value = $1 AND (version = $2 OR version = '')
 		ORDER BY date_ingested DESC
@@ -147,6 +248,8 @@ func (r *PostgresRepository) FindByValueAndVersion(ctx context.Context, value, v
 			&ioc.Version,
 			&ioc.FirstSeen,
 			&ioc.DateIngested,
+			&ioc.Confidence,
+			&ioc.LastSeen,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan IOC: %w", err)
@@ -205,9 +308,95 @@ func (r *PostgresRepository) FindContaining(ctx context.Context, value string) (
 	return iocs, nil
 }
 
+// FindChangesSince implements the decisions-stream delta query. It assumes the
+// iocs table carries `deleted_at`/`expires_at` columns (see ports.IOCRepository
+// for the rationale): rows newly ingested since the watermark are "added", and
+// rows that were deleted or expired since the watermark are "removed".
+func (r *PostgresRepository) FindChangesSince(ctx context.Context, since time.Time, scopes []domain.IOCType) ([]domain.IOC, []domain.IOC, error) {
+	typeFilter := ""
+	args := []interface{}{since}
+	if len(scopes) > 0 {
+		typeFilter = " AND type = ANY($2)"
+		args = append(args, scopes)
+	}
+
+	addedQuery := `
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested
+		FROM iocs
+		WHERE date_ingested > $1 AND deleted_at IS NULL` + typeFilter + `
+		ORDER BY date_ingested ASC
+	`
+
+	added, err := r.scanIOCRows(ctx, addedQuery, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query added IOCs: %w", err)
+	}
+
+	removedQuery := `
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested
+		FROM iocs
+		WHERE (deleted_at > $1 OR (expires_at > $1 AND expires_at <= NOW()))` + typeFilter + `
+		ORDER BY COALESCE(deleted_at, expires_at) ASC
+	`
+
+	removed, err := r.scanIOCRows(ctx, removedQuery, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query removed IOCs: %w", err)
+	}
+
+	return added, removed, nil
+}
+
+// LatestIngested implements ports.IOCRepository.
+func (r *PostgresRepository) LatestIngested(ctx context.Context) (time.Time, error) {
+	var latest *time.Time
+	err := r.db.QueryRow(ctx, `SELECT MAX(date_ingested) FROM iocs`).Scan(&latest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query latest date_ingested: %w", err)
+	}
+	if latest == nil {
+		return time.Time{}, nil
+	}
+	return *latest, nil
+}
+
+// scanIOCRows runs a query expected to return the standard IOC column set and
+// scans every row into a domain.IOC slice.
+func (r *PostgresRepository) scanIOCRows(ctx context.Context, query string, args ...interface{}) ([]domain.IOC, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var iocs []domain.IOC
+	for rows.Next() {
+		var ioc domain.IOC
+		if err := rows.Scan(
+			&ioc.Value,
+			&ioc.Type,
+			&ioc.Source,
+			&ioc.ThreatType,
+			&ioc.Tags,
+			&ioc.Version,
+			&ioc.FirstSeen,
+			&ioc.DateIngested,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan IOC: %w", err)
+		}
+		iocs = append(iocs, ioc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return iocs, nil
+}
+
 func (r *PostgresRepository) FindSince(ctx context.Context, since time.Time, limit int) ([]domain.IOC, error) {
 	query := `
-		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested, confidence, COALESCE(last_seen, date_ingested)
 		FROM iocs
 		WHERE date_ingested >= $1
 		ORDER BY date_ingested DESC
@@ -233,6 +422,8 @@ func (r *PostgresRepository) FindSince(ctx context.Context, since time.Time, lim
 			&ioc.Version,
 			&ioc.FirstSeen,
 			&ioc.DateIngested,
+			&ioc.Confidence,
+			&ioc.LastSeen,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan IOC: %w", err)
@@ -246,3 +437,116 @@ func (r *PostgresRepository) FindSince(ctx context.Context, since time.Time, lim
 
 	return iocs, nil
 }
+
+// streamFetchSize is how many rows StreamSince pulls from the cursor per
+// round trip. It bounds memory per batch regardless of how many rows match
+// the query as a whole.
+const streamFetchSize = 500
+
+// StreamSince walks matching IOCs via a server-side cursor instead of
+// materializing the whole result set, so exporters can serve feeds far
+// larger than comfortably fits in memory.
+func (r *PostgresRepository) StreamSince(ctx context.Context, since time.Time, limit int, fn func(domain.IOC) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin stream transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		DECLARE ioc_stream CURSOR FOR
+		SELECT value, type, source, threat_type, tags, version, first_seen, date_ingested, confidence, COALESCE(last_seen, date_ingested)
+		FROM iocs
+		WHERE date_ingested >= $1
+		ORDER BY date_ingested DESC
+	`, since); err != nil {
+		return fmt.Errorf("failed to declare IOC stream cursor: %w", err)
+	}
+
+	delivered := 0
+	for {
+		fetch := streamFetchSize
+		if limit > 0 {
+			if remaining := limit - delivered; remaining < fetch {
+				fetch = remaining
+			}
+			if fetch <= 0 {
+				break
+			}
+		}
+
+		n, err := r.fetchStreamBatch(ctx, tx, fetch, fn)
+		if err != nil {
+			return err
+		}
+		delivered += n
+		if n < fetch {
+			break
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// fetchStreamBatch pulls up to n rows from the open ioc_stream cursor and
+// delivers each to fn, returning how many rows it fetched.
+func (r *PostgresRepository) fetchStreamBatch(ctx context.Context, tx pgx.Tx, n int, fn func(domain.IOC) error) (int, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM ioc_stream", n))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch IOC stream batch: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		var ioc domain.IOC
+		if err := rows.Scan(
+			&ioc.Value,
+			&ioc.Type,
+			&ioc.Source,
+			&ioc.ThreatType,
+			&ioc.Tags,
+			&ioc.Version,
+			&ioc.FirstSeen,
+			&ioc.DateIngested,
+			&ioc.Confidence,
+			&ioc.LastSeen,
+		); err != nil {
+			return fetched, fmt.Errorf("failed to scan IOC: %w", err)
+		}
+		fetched++
+		if err := fn(ioc); err != nil {
+			return fetched, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fetched, fmt.Errorf("error iterating IOC stream rows: %w", err)
+	}
+
+	return fetched, nil
+}
+
+// FindByValuesIn looks up many values in a single round trip via = ANY($1),
+// for bulk callers (e.g. BulkCheckIOC) that would otherwise pay one query per
+// value. Each matching row keeps only the most recently ingested entry per
+// value, matching FindByValue's "LIMIT 1" semantics.
+func (r *PostgresRepository) FindByValuesIn(ctx context.Context, values []string) ([]domain.IOC, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT DISTINCT ON (value) value, type, source, threat_type, tags, version, first_seen, date_ingested
+		FROM iocs
+		WHERE value = ANY($1)
+		ORDER BY value, date_ingested DESC
+	`
+
+	iocs, err := r.scanIOCRows(ctx, query, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query IOCs by values: %w", err)
+	}
+
+	return iocs, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresAgentRepository stores enrolled agents (webhooks, bouncers, admins)
+// in the `agents` table alongside the existing `iocs` table.
+type PostgresAgentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAgentRepository(db *pgxpool.Pool) *PostgresAgentRepository {
+	return &PostgresAgentRepository{db: db}
+}
+
+func (r *PostgresAgentRepository) Create(ctx context.Context, agent ports.Agent) error {
+	query := `
+		INSERT INTO agents (agent_id, name, role, hashed_api_key, tls_cert_fingerprint, enrolled_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		agent.AgentID,
+		agent.Name,
+		agent.Role,
+		agent.HashedAPIKey,
+		agent.TLSCertFingerprint,
+		agent.EnrolledAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enroll agent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAgentRepository) FindByAPIKeyHash(ctx context.Context, hashedAPIKey string) (*ports.Agent, error) {
+	query := `
+		SELECT agent_id, name, role, hashed_api_key, tls_cert_fingerprint, enrolled_at, revoked_at
+		FROM agents
+		WHERE hashed_api_key = $1
+	`
+	return r.scanAgent(ctx, query, hashedAPIKey)
+}
+
+func (r *PostgresAgentRepository) FindByCertFingerprint(ctx context.Context, fingerprint string) (*ports.Agent, error) {
+	query := `
+		SELECT agent_id, name, role, hashed_api_key, tls_cert_fingerprint, enrolled_at, revoked_at
+		FROM agents
+		WHERE tls_cert_fingerprint = $1
+	`
+	return r.scanAgent(ctx, query, fingerprint)
+}
+
+func (r *PostgresAgentRepository) scanAgent(ctx context.Context, query string, arg string) (*ports.Agent, error) {
+	var agent ports.Agent
+
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&agent.AgentID,
+		&agent.Name,
+		&agent.Role,
+		&agent.HashedAPIKey,
+		&agent.TLSCertFingerprint,
+		&agent.EnrolledAt,
+		&agent.RevokedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to look up agent: %w", err)
+	}
+
+	return &agent, nil
+}
+
+func (r *PostgresAgentRepository) List(ctx context.Context) ([]ports.Agent, error) {
+	query := `
+		SELECT agent_id, name, role, hashed_api_key, tls_cert_fingerprint, enrolled_at, revoked_at
+		FROM agents
+		ORDER BY enrolled_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []ports.Agent
+	for rows.Next() {
+		var agent ports.Agent
+		if err := rows.Scan(
+			&agent.AgentID,
+			&agent.Name,
+			&agent.Role,
+			&agent.HashedAPIKey,
+			&agent.TLSCertFingerprint,
+			&agent.EnrolledAt,
+			&agent.RevokedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return agents, nil
+}
+
+func (r *PostgresAgentRepository) Revoke(ctx context.Context, agentID string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE agents SET revoked_at = now() WHERE agent_id = $1 AND revoked_at IS NULL`, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke agent: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("agent %s not found or already revoked", agentID)
+	}
+	return nil
+}
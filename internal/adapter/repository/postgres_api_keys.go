@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hive-corporation/watchtower/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresAPIKeyRepository stores public REST API credentials in the
+// `api_keys` table alongside the existing `agents` table.
+type PostgresAPIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAPIKeyRepository(db *pgxpool.Pool) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key ports.APIKey) error {
+	query := `
+		INSERT INTO api_keys (key_id, name, hashed_key, scopes, rate_qps, rate_burst, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		key.KeyID,
+		key.Name,
+		key.HashedKey,
+		scopesToStrings(key.Scopes),
+		key.RateQPS,
+		key.RateBurst,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAPIKeyRepository) FindByHash(ctx context.Context, hashedKey string) (*ports.APIKey, error) {
+	query := `
+		SELECT key_id, name, hashed_key, scopes, rate_qps, rate_burst, created_at, revoked_at
+		FROM api_keys
+		WHERE hashed_key = $1
+	`
+	return r.scanAPIKey(ctx, query, hashedKey)
+}
+
+func (r *PostgresAPIKeyRepository) scanAPIKey(ctx context.Context, query string, arg string) (*ports.APIKey, error) {
+	var key ports.APIKey
+	var scopes []string
+
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&key.KeyID,
+		&key.Name,
+		&key.HashedKey,
+		&scopes,
+		&key.RateQPS,
+		&key.RateBurst,
+		&key.CreatedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to look up API key: %w", err)
+	}
+
+	key.Scopes = stringsToScopes(scopes)
+	return &key, nil
+}
+
+func (r *PostgresAPIKeyRepository) List(ctx context.Context) ([]ports.APIKey, error) {
+	query := `
+		SELECT key_id, name, hashed_key, scopes, rate_qps, rate_burst, created_at, revoked_at
+		FROM api_keys
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []ports.APIKey
+	for rows.Next() {
+		var key ports.APIKey
+		var scopes []string
+		if err := rows.Scan(
+			&key.KeyID,
+			&key.Name,
+			&key.HashedKey,
+			&scopes,
+			&key.RateQPS,
+			&key.RateBurst,
+			&key.CreatedAt,
+			&key.RevokedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		key.Scopes = stringsToScopes(scopes)
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, keyID string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE api_keys SET revoked_at = now() WHERE key_id = $1 AND revoked_at IS NULL`, keyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("API key %s not found or already revoked", keyID)
+	}
+	return nil
+}
+
+func scopesToStrings(scopes []ports.APIKeyScope) []string {
+	out := make([]string, len(scopes))
+	for i, s := range scopes {
+		out[i] = string(s)
+	}
+	return out
+}
+
+func stringsToScopes(scopes []string) []ports.APIKeyScope {
+	out := make([]ports.APIKeyScope, len(scopes))
+	for i, s := range scopes {
+		out[i] = ports.APIKeyScope(s)
+	}
+	return out
+}
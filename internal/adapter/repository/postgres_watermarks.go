@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresWatermarkRepository tracks the last-synced timestamp per provider
+// in the `provider_watermarks` table, so incremental feeds like OSVProvider
+// don't reprocess entries they've already ingested.
+type PostgresWatermarkRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresWatermarkRepository(db *pgxpool.Pool) *PostgresWatermarkRepository {
+	return &PostgresWatermarkRepository{db: db}
+}
+
+func (r *PostgresWatermarkRepository) GetWatermark(ctx context.Context, provider string) (time.Time, error) {
+	var at time.Time
+	err := r.db.QueryRow(ctx, `SELECT last_synced_at FROM provider_watermarks WHERE provider = $1`, provider).Scan(&at)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to read watermark for %s: %w", provider, err)
+	}
+	return at, nil
+}
+
+func (r *PostgresWatermarkRepository) SetWatermark(ctx context.Context, provider string, at time.Time) error {
+	query := `
+		INSERT INTO provider_watermarks (provider, last_synced_at)
+		VALUES ($1, $2)
+		ON CONFLICT (provider) DO UPDATE SET last_synced_at = EXCLUDED.last_synced_at
+	`
+	if _, err := r.db.Exec(ctx, query, provider, at); err != nil {
+		return fmt.Errorf("failed to save watermark for %s: %w", provider, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// HashAPIKey returns the SHA-256 hex digest used to key APIKeyConfig.Keys, so
+// raw API keys are never held in server configuration or logs. Mirrors
+// handler.HashAPIKey's approach for the REST API's agent enrollment keys,
+// kept as its own copy since gRPC tenant keys are a distinct credential
+// space from enrolled REST agents.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyFileEntry is the on-disk shape for an API-key config file, keyed by
+// the SHA-256 hex digest of the raw key.
+type apiKeyFileEntry struct {
+	TenantID  string   `json:"tenant_id"`
+	Roles     []string `json:"roles"`
+	RateQPS   float64  `json:"rate_qps"`
+	RateBurst int      `json:"rate_burst"`
+}
+
+// LoadAPIKeysFromFile reads a JSON file of the form
+// {"<sha256 hex of key>": {"tenant_id": "...", "roles": ["reader"], "rate_qps": 5, "rate_burst": 10}}
+// into the map APIKeyConfig.Keys expects.
+func LoadAPIKeysFromFile(path string) (map[string]APIKeyPrincipal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API key file: %w", err)
+	}
+
+	var entries map[string]apiKeyFileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse API key file: %w", err)
+	}
+
+	keys := make(map[string]APIKeyPrincipal, len(entries))
+	for keyID, entry := range entries {
+		keys[keyID] = APIKeyPrincipal{
+			TenantID:  entry.TenantID,
+			Roles:     entry.Roles,
+			RateQPS:   entry.RateQPS,
+			RateBurst: entry.RateBurst,
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,349 @@
+// Package security provides gRPC authN/authZ interceptors for the
+// Watchtower API: mTLS with SPIFFE-style SAN allowlisting, per-tenant API
+// keys with token-bucket rate limits, and OIDC bearer tokens validated
+// against a JWKS endpoint with claim-based RBAC. Exactly one of these is
+// usually configured per deployment, but AuthInterceptor accepts whichever
+// combination is enabled and authenticates with the first one that matches
+// the incoming request, so a server can support several caller types (e.g.
+// mTLS services + OIDC-authenticated humans) at once.
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Principal is the authenticated caller attached to the context by
+// AuthInterceptor, so handlers can filter results by tenant or branch on role
+// without re-deriving identity from transport/header state.
+type Principal struct {
+	// TenantID scopes API-key and OIDC callers to their own data. mTLS
+	// principals use the SPIFFE ID (or cert subject) as TenantID.
+	TenantID string
+	// Subject is the caller identity: the cert's SAN for mTLS, the API key
+	// ID for API-key auth, or the JWT's "sub" claim for OIDC.
+	Subject string
+	// Roles gates RBAC-sensitive RPCs (e.g. "reader", "writer").
+	Roles []string
+	// AuthMode records which scheme authenticated this principal ("mtls",
+	// "api_key", "oidc"), useful for audit logging.
+	AuthMode string
+}
+
+// HasRole reports whether the principal holds role.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// PrincipalFromContext returns the principal AuthInterceptor attached to ctx.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalContextKey{}).(Principal)
+	return p, ok
+}
+
+// MTLSConfig enables certificate-based authentication: the gRPC server's own
+// TLS credentials must already require and verify client certs against
+// ClientCAs (AuthInterceptor only re-checks the SAN allowlist, it doesn't
+// perform the TLS handshake itself).
+type MTLSConfig struct {
+	// AllowedSANs restricts which verified client certificates are accepted,
+	// matched against both URI SANs (for SPIFFE IDs like
+	// "spiffe://watchtower/ns/prod/sa/scanner") and DNS SANs. Empty means
+	// any certificate signed by a CA the listener trusts is accepted.
+	AllowedSANs []string
+	// Roles assigned to every mTLS-authenticated principal. mTLS callers are
+	// typically trusted services, so a single static role set is usually
+	// enough; tenant isolation instead comes from the cert's SAN.
+	Roles []string
+}
+
+// APIKeyPrincipal is the tenant, roles, and rate limit bound to one API key.
+type APIKeyPrincipal struct {
+	TenantID string
+	Roles    []string
+	// RateQPS/RateBurst configure this key's token bucket. RateQPS <= 0
+	// disables rate limiting for the key.
+	RateQPS   float64
+	RateBurst int
+}
+
+// APIKeyConfig enables API-key authentication via the "x-api-key" metadata
+// header.
+type APIKeyConfig struct {
+	// Keys maps a key's SHA-256 hex digest (see HashAPIKey) to the
+	// tenant/roles/rate limit it grants, mirroring how handler.AuthMiddleware
+	// never stores raw keys.
+	Keys map[string]APIKeyPrincipal
+}
+
+// OIDCConfig enables bearer-token authentication validated against a JWKS
+// endpoint.
+type OIDCConfig struct {
+	// JWKSURL is polled (and cached) for the signing keys, e.g.
+	// "https://idp.example.com/.well-known/jwks.json".
+	JWKSURL string
+	// Issuer/Audience, when set, are checked against the token's "iss"/"aud"
+	// claims.
+	Issuer   string
+	Audience string
+	// RolesClaim is the claim holding the caller's roles, e.g.
+	// `{"roles": ["reader", "writer"]}`. Defaults to "roles".
+	RolesClaim string
+}
+
+// DefaultMethodRoles is the RBAC policy for Watchtower's current gRPC
+// service: every existing RPC only reads data, so "reader" or "writer" both
+// suffice. A future ingestion RPC should be added here requiring "writer"
+// only, so read-only API keys/tokens can't push data into the database.
+var DefaultMethodRoles = map[string][]string{
+	"/watchtower.Watchtower/CheckIOC":     {"reader", "writer"},
+	"/watchtower.Watchtower/SearchIOC":    {"reader", "writer"},
+	"/watchtower.Watchtower/BulkCheckIOC": {"reader", "writer"},
+}
+
+// AuthInterceptorConfig assembles the auth schemes an AuthInterceptor
+// accepts. Zero-value sub-configs (nil pointer) disable that scheme.
+type AuthInterceptorConfig struct {
+	MTLS   *MTLSConfig
+	APIKey *APIKeyConfig
+	OIDC   *OIDCConfig
+
+	// MethodRoles restricts which role a principal must hold to call a given
+	// full gRPC method (e.g. "/watchtower.Watchtower/CheckIOC"). A method
+	// absent from this map is open to any authenticated principal.
+	MethodRoles map[string][]string
+}
+
+// AuthInterceptor installs authentication and RBAC for the gRPC server.
+type AuthInterceptor struct {
+	config      AuthInterceptorConfig
+	jwks        *jwksVerifier
+	rateLimiter *TokenBucketLimiter
+}
+
+// NewAuthInterceptor builds an AuthInterceptor from config, starting a JWKS
+// verifier when OIDC is enabled.
+func NewAuthInterceptor(config AuthInterceptorConfig) *AuthInterceptor {
+	ai := &AuthInterceptor{
+		config:      config,
+		rateLimiter: NewTokenBucketLimiter(),
+	}
+	if config.OIDC != nil {
+		ai.jwks = newJWKSVerifier(config.OIDC.JWKSURL)
+	}
+	return ai
+}
+
+// Unary returns the unary server interceptor to register with grpc.NewServer.
+func (ai *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := ai.authenticateAndAuthorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the stream server interceptor to register with grpc.NewServer.
+func (ai *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := ai.authenticateAndAuthorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream overrides Context() so handlers reading from the
+// stream observe the principal-bearing context rather than the raw one.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context { return s.ctx }
+
+// authenticateAndAuthorize resolves a principal via whichever scheme matches
+// the request, enforces its rate limit, checks RBAC for fullMethod, and
+// returns ctx with the principal attached.
+func (ai *AuthInterceptor) authenticateAndAuthorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	principal, err := ai.authenticate(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if principal.AuthMode == "api_key" {
+		qps, burst := ai.apiKeyRate(principal.Subject)
+		allowed, err := ai.rateLimiter.Allow(principal.Subject, qps, burst)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "rate limiter error")
+		}
+		if !allowed {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded for API key")
+		}
+	}
+
+	if requiredRoles, ok := ai.config.MethodRoles[fullMethod]; ok {
+		if !hasAnyRole(principal, requiredRoles) {
+			return nil, status.Errorf(codes.PermissionDenied, "principal lacks required role for %s", fullMethod)
+		}
+	}
+
+	return context.WithValue(ctx, principalContextKey{}, principal), nil
+}
+
+func hasAnyRole(p Principal, required []string) bool {
+	for _, role := range required {
+		if p.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiKeyRate looks up the configured token bucket rate for an API key's
+// subject (its key ID), defaulting to "no limit" if the key isn't found
+// (authenticate would already have rejected an unknown key).
+func (ai *AuthInterceptor) apiKeyRate(keyID string) (float64, int) {
+	if ai.config.APIKey == nil {
+		return 0, 0
+	}
+	if p, ok := ai.config.APIKey.Keys[keyID]; ok {
+		return p.RateQPS, p.RateBurst
+	}
+	return 0, 0
+}
+
+// authenticate tries each enabled scheme in turn: mTLS (from the transport's
+// peer certificates), then the "x-api-key" header, then an OIDC bearer
+// token. The first scheme that is both enabled and has matching credentials
+// on the request wins.
+func (ai *AuthInterceptor) authenticate(ctx context.Context) (Principal, error) {
+	if ai.config.MTLS != nil {
+		if principal, ok := ai.authenticateMTLS(ctx); ok {
+			return principal, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if ai.config.APIKey != nil {
+		if key := firstValue(md, "x-api-key"); key != "" {
+			return ai.authenticateAPIKey(key)
+		}
+	}
+
+	if ai.config.OIDC != nil {
+		if token := bearerToken(md); token != "" {
+			return ai.authenticateOIDC(ctx, token)
+		}
+	}
+
+	return Principal{}, errors.New("no valid credentials presented")
+}
+
+func (ai *AuthInterceptor) authenticateMTLS(ctx context.Context) (Principal, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Principal{}, false
+	}
+
+	cert, ok := peerLeafCert(p)
+	if !ok {
+		return Principal{}, false
+	}
+
+	san, ok := matchAllowedSAN(cert, ai.config.MTLS.AllowedSANs)
+	if !ok {
+		return Principal{}, false
+	}
+
+	return Principal{
+		TenantID: san,
+		Subject:  san,
+		Roles:    ai.config.MTLS.Roles,
+		AuthMode: "mtls",
+	}, true
+}
+
+func (ai *AuthInterceptor) authenticateAPIKey(rawKey string) (Principal, error) {
+	keyID := HashAPIKey(rawKey)
+	principal, ok := ai.config.APIKey.Keys[keyID]
+	if !ok {
+		return Principal{}, errors.New("invalid API key")
+	}
+	return Principal{
+		TenantID: principal.TenantID,
+		Subject:  keyID,
+		Roles:    principal.Roles,
+		AuthMode: "api_key",
+	}, nil
+}
+
+func (ai *AuthInterceptor) authenticateOIDC(ctx context.Context, token string) (Principal, error) {
+	claims, err := ai.jwks.Verify(ctx, token, ai.config.OIDC.Issuer, ai.config.OIDC.Audience)
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid OIDC token: %w", err)
+	}
+
+	rolesClaim := ai.config.OIDC.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = "roles"
+	}
+
+	var roles []string
+	if raw, ok := claims[rolesClaim].([]interface{}); ok {
+		for _, r := range raw {
+			if s, ok := r.(string); ok {
+				roles = append(roles, s)
+			}
+		}
+	}
+
+	subject, _ := claims["sub"].(string)
+	tenant, _ := claims["tenant"].(string)
+	if tenant == "" {
+		tenant = subject
+	}
+
+	return Principal{
+		TenantID: tenant,
+		Subject:  subject,
+		Roles:    roles,
+		AuthMode: "oidc",
+	}, nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func bearerToken(md metadata.MD) string {
+	header := firstValue(md, "authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
@@ -0,0 +1,63 @@
+package security
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// peerLeafCert extracts the client's leaf certificate from a gRPC peer,
+// assuming the listener's own TLS config already required and verified it
+// (AuthInterceptor only re-checks the SAN allowlist below, it never performs
+// the handshake itself).
+func peerLeafCert(p *peer.Peer) (*x509.Certificate, bool) {
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, false
+	}
+	state := tlsInfo.State
+	if len(state.PeerCertificates) == 0 {
+		return nil, false
+	}
+	return state.PeerCertificates[0], true
+}
+
+// matchAllowedSAN reports whether cert carries a URI SAN (for SPIFFE IDs
+// like "spiffe://watchtower/ns/prod/sa/scanner") or DNS SAN in allowed, and
+// returns the matching identifier. An empty allowed list accepts any
+// certificate the listener's CA pool already verified.
+func matchAllowedSAN(cert *x509.Certificate, allowed []string) (string, bool) {
+	if len(allowed) == 0 {
+		return cert.Subject.CommonName, true
+	}
+
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, san := range allowed {
+		allowedSet[san] = true
+	}
+
+	for _, uri := range cert.URIs {
+		if allowedSet[uri.String()] {
+			return uri.String(), true
+		}
+	}
+	for _, dns := range cert.DNSNames {
+		if allowedSet[dns] {
+			return dns, true
+		}
+	}
+
+	return "", false
+}
+
+// NewServerTLSConfig builds a tls.Config that requires and verifies client
+// certificates against caPool, for a gRPC listener enabling MTLSConfig.
+func NewServerTLSConfig(serverCert tls.Certificate, caPool *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucketLimiter enforces a per-key token bucket rate limit in process.
+// It's intentionally simple (no shared/Redis backing, unlike
+// llm.SharedStateBackend's rate limiter) since API-key limits here bound a
+// single gateway replica's exposure to one tenant, not a shared upstream
+// budget across replicas. Exported so REST-facing callers (handler package)
+// can reuse the same bucketing logic instead of re-implementing it.
+type TokenBucketLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens     float64
+	qps        float64
+	burst      int
+	lastRefill time.Time
+}
+
+func NewTokenBucketLimiter() *TokenBucketLimiter {
+	return &TokenBucketLimiter{buckets: make(map[string]*bucket)}
+}
+
+// Allow reports whether a request for key may proceed, refilling its bucket
+// at qps tokens/sec up to burst capacity since the last call. qps <= 0
+// disables limiting (always allowed), matching APIKeyPrincipal's "<=0 means
+// unlimited" convention.
+func (l *TokenBucketLimiter) Allow(key string, qps float64, burst int) (bool, error) {
+	if qps <= 0 {
+		return true, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), qps: qps, burst: burst, lastRefill: now}
+		l.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens += elapsed * b.qps
+	if b.tokens > float64(b.burst) {
+		b.tokens = float64(b.burst)
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+	return true, nil
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// CrowdStrikeAdapter verifies and parses a CrowdStrike Falcon detection
+// webhook payload.
+type CrowdStrikeAdapter struct {
+	verifier *HMACVerifier
+}
+
+// NewCrowdStrikeAdapter builds an adapter reading its shared secret from
+// CROWDSTRIKE_WEBHOOK_SECRET; Verify stays open when it's unset.
+func NewCrowdStrikeAdapter() *CrowdStrikeAdapter {
+	return &CrowdStrikeAdapter{
+		verifier: &HMACVerifier{
+			Secret:          os.Getenv("CROWDSTRIKE_WEBHOOK_SECRET"),
+			SignatureHeader: "X-CS-Signature",
+			TimestampHeader: "X-CS-Timestamp",
+		},
+	}
+}
+
+func (a *CrowdStrikeAdapter) Name() string { return "crowdstrike" }
+
+func (a *CrowdStrikeAdapter) Verify(r *http.Request, body []byte) error {
+	return a.verifier.Verify(r, body)
+}
+
+type crowdStrikeBehavior struct {
+	Tactic    string `json:"tactic"`
+	Technique string `json:"technique"`
+	IOCType   string `json:"ioc_type"`
+	IOCValue  string `json:"ioc_value"`
+}
+
+type crowdStrikePayload struct {
+	DetectionID string                `json:"detection_id"`
+	Severity    string                `json:"max_severity_displayname"`
+	Behaviors   []crowdStrikeBehavior `json:"behaviors"`
+	Device      struct {
+		Hostname     string `json:"hostname"`
+		PlatformName string `json:"platform_name"`
+	} `json:"device"`
+}
+
+func (a *CrowdStrikeAdapter) Parse(body []byte) (CanonicalAlert, error) {
+	var payload crowdStrikePayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return CanonicalAlert{}, fmt.Errorf("crowdstrike: failed to decode payload: %w", err)
+	}
+
+	alert := CanonicalAlert{
+		Source:         a.Name(),
+		AlertID:        payload.DetectionID,
+		ThreatName:     primaryTactic(payload.Behaviors),
+		Classification: payload.Severity,
+		Endpoint:       payload.Device.Hostname,
+		OSType:         payload.Device.PlatformName,
+	}
+	for _, behavior := range payload.Behaviors {
+		if behavior.IOCValue == "" {
+			continue
+		}
+		alert.Indicators = append(alert.Indicators, CanonicalIndicator{Type: behavior.IOCType, Value: behavior.IOCValue})
+	}
+	return alert, nil
+}
+
+// primaryTactic summarizes a detection's first reported behavior as a
+// threat name, since Falcon detections don't carry one directly.
+func primaryTactic(behaviors []crowdStrikeBehavior) string {
+	if len(behaviors) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", behaviors[0].Tactic, behaviors[0].Technique)
+}
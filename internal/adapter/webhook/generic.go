@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// GenericAdapter parses the Falco JSON output schema
+// (https://falco.org/docs/outputs/), which a compatible OSQuery forwarder
+// can also target: {"rule", "output", "priority", "hostname",
+// "output_fields": {...}}.
+type GenericAdapter struct {
+	verifier *HMACVerifier
+}
+
+// NewGenericAdapter builds an adapter reading its shared secret from
+// GENERIC_WEBHOOK_SECRET; Verify stays open when it's unset.
+func NewGenericAdapter() *GenericAdapter {
+	return &GenericAdapter{
+		verifier: &HMACVerifier{
+			Secret:          os.Getenv("GENERIC_WEBHOOK_SECRET"),
+			SignatureHeader: "X-Webhook-Signature",
+			TimestampHeader: "X-Webhook-Timestamp",
+		},
+	}
+}
+
+func (a *GenericAdapter) Name() string { return "generic" }
+
+func (a *GenericAdapter) Verify(r *http.Request, body []byte) error {
+	return a.verifier.Verify(r, body)
+}
+
+// genericIndicatorFields lists the output_fields keys that carry an
+// IOC-like value worth enriching, keyed by the CanonicalIndicator type
+// they map to.
+var genericIndicatorFields = map[string]string{
+	"fd.rip":  "IPV4",
+	"fd.sip":  "IPV4",
+	"fd.name": "FILEPATH",
+}
+
+type genericPayload struct {
+	Rule         string            `json:"rule"`
+	Output       string            `json:"output"`
+	Priority     string            `json:"priority"`
+	Hostname     string            `json:"hostname"`
+	OutputFields map[string]string `json:"output_fields"`
+}
+
+func (a *GenericAdapter) Parse(body []byte) (CanonicalAlert, error) {
+	var payload genericPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return CanonicalAlert{}, fmt.Errorf("generic: failed to decode payload: %w", err)
+	}
+
+	alert := CanonicalAlert{
+		Source:         a.Name(),
+		AlertID:        payload.Rule,
+		ThreatName:     payload.Output,
+		Classification: payload.Priority,
+		Endpoint:       payload.Hostname,
+	}
+	for field, iocType := range genericIndicatorFields {
+		if value, ok := payload.OutputFields[field]; ok && value != "" {
+			alert.Indicators = append(alert.Indicators, CanonicalIndicator{Type: iocType, Value: value})
+		}
+	}
+	return alert, nil
+}
@@ -0,0 +1,44 @@
+// Package webhook normalizes inbound EDR/sensor alert webhooks (SentinelOne,
+// CrowdStrike Falcon, Falco/OSQuery) into a single CanonicalAlert shape, so
+// the REST handler can run one enrichment/LLM/notification pipeline instead
+// of duplicating it per source.
+package webhook
+
+import (
+	"net/http"
+	"time"
+)
+
+// CanonicalAlert is the normalized shape every Adapter parses its source's
+// native payload into.
+type CanonicalAlert struct {
+	Source         string
+	AlertID        string
+	ThreatName     string
+	Classification string
+	Endpoint       string
+	OSType         string
+	Timestamp      time.Time
+	Indicators     []CanonicalIndicator
+}
+
+// CanonicalIndicator is one IOC-like value carried on a CanonicalAlert, in
+// the same Type/Value shape CheckIOC and the exporters already use.
+type CanonicalIndicator struct {
+	Type  string
+	Value string
+}
+
+// Adapter verifies and parses one webhook source's payload. Verify runs
+// against the raw, unparsed body so a signature can be checked before any
+// JSON decoding happens; Parse only needs to be called once Verify passes.
+type Adapter interface {
+	// Name identifies the adapter for logging and error messages, e.g.
+	// "sentinelone".
+	Name() string
+	// Verify checks r's signature/timestamp headers against body, the exact
+	// bytes read off the wire.
+	Verify(r *http.Request, body []byte) error
+	// Parse decodes body into a CanonicalAlert.
+	Parse(body []byte) (CanonicalAlert, error)
+}
@@ -10,6 +10,12 @@ const (
 	FileHash  IOCType = "file_hash"
 	URL       IOCType = "url"
 	Package   IOCType = "package"
+	Email     IOCType = "email"
+	CVE       IOCType = "cve"
+	YARA      IOCType = "yara"
+	Mutex     IOCType = "mutex"
+	FileName  IOCType = "file_name"
+	FilePath  IOCType = "file_path"
 )
 
 type IOC struct {
@@ -21,6 +27,31 @@ type IOC struct {
 	Version      string    // Versão afetada (apenas para pacotes, vazio para outros tipos)
 	FirstSeen    time.Time // Quando a fonte viu isso pela primeira vez
 	DateIngested time.Time // Quando NÓS processamos isso
+
+	ExpiresAt time.Time  // Quando este IOC deve ser considerado expirado (zero = nunca)
+	DeletedAt *time.Time // Quando este IOC foi removido/revogado (nil = ativo)
+
+	// ProviderConfidence is the confidence (0-100) the upstream source itself
+	// reported for this sighting (e.g. a STIX indicator's "confidence"
+	// property), as opposed to CalculateConfidenceScore's own derived score.
+	// Zero means the source didn't report one.
+	ProviderConfidence int
+
+	// Confidence is CalculateConfidenceScore's derived score (0-100) across
+	// every sighting of this value. It's recomputed whenever SaveBatch saves
+	// a new sighting for it, and also on a schedule for values that haven't
+	// had one in a while (see PostgresRepository.RecomputeStaleConfidence),
+	// so recencyDecay's time-since-DateIngested term keeps pulling the score
+	// down even between sightings. Unlike ProviderConfidence it isn't
+	// per-source - every row sharing the same Value carries the same
+	// Confidence.
+	Confidence int
+
+	// LastSeen is the most recent DateIngested across every sighting of this
+	// value, independent of which source this particular row came from. It
+	// drives CalculateConfidenceScore's time-decay term, so a value nobody
+	// has reported recently fades out instead of alerting forever.
+	LastSeen time.Time
 }
 
 /*
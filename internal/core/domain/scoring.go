@@ -1,22 +1,238 @@
 package domain
 
-// CalculateConfidenceScore calculates an overall confidence score from multiple IOC sightings.
-// This is a pure domain function with no I/O dependencies.
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ScoringConfig holds the tunables CalculateConfidenceScore combines sightings
+// with, so reputation weights and decay rates can be adjusted without
+// touching domain code. Zero-value fields fall back to DefaultScoringConfig's
+// defaults inside CalculateConfidenceScore, so callers that only care about a
+// couple of overrides don't have to repeat every default.
+type ScoringConfig struct {
+	// SourceWeights is the per-source reputation weight w_i, keyed by exact
+	// ioc.Source value (e.g. "abusech-urlhaus").
+	SourceWeights map[string]float64
+
+	// SourcePrefixWeights matches sources by prefix, for providers that emit
+	// one source string per sub-feed (e.g. OSV's "google-osv-<ecosystem>").
+	// The longest matching prefix wins when more than one matches.
+	SourcePrefixWeights map[string]float64
+
+	// DefaultSourceWeight is used when a source matches neither map above.
+	DefaultSourceWeight float64
+
+	// ReliabilityBumps maps a tag (or tag prefix ending in "*") to the
+	// reliability bump it contributes toward r_i. Bumps from every matching
+	// tag on a sighting are summed.
+	ReliabilityBumps map[string]float64
+
+	// HalfLifeDays is the exponential decay half-life used for d_i: a
+	// sighting's contribution halves every HalfLifeDays days since DateIngested.
+	// It's the fallback for threat types not listed in HalfLifeDaysByThreatType.
+	HalfLifeDays float64
+
+	// HalfLifeDaysByThreatType overrides HalfLifeDays per ioc.ThreatType, since
+	// different threats go stale at very different rates: botnet C2
+	// infrastructure typically gets torn down or reused within days, while a
+	// compromised package release stays dangerous for as long as it's
+	// installable, i.e. months.
+	HalfLifeDaysByThreatType map[string]float64
+
+	// DedupeWindow collapses multiple sightings from the same source into
+	// one when they fall within this window of each other, so repeated
+	// scraper runs of the same feed don't inflate the score.
+	DedupeWindow time.Duration
+}
+
+// DefaultScoringConfig returns the weights used when the caller doesn't
+// override ScoringConfig, calibrated against the providers this repository
+// currently ships (urlhaus.go, otx.go, osv.go).
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		SourceWeights: map[string]float64{
+			"abusech-urlhaus": 0.9,
+			"alienvault-otx":  0.6,
+		},
+		SourcePrefixWeights: map[string]float64{
+			"google-osv-": 0.95,
+		},
+		DefaultSourceWeight: 0.5,
+		ReliabilityBumps: map[string]float64{
+			"verified":         0.1,
+			"malware-family:*": 0.1,
+		},
+		HalfLifeDays: 30,
+		HalfLifeDaysByThreatType: map[string]float64{
+			"c2_server":            7,
+			"botnet":               7,
+			"supply_chain_malware": 90,
+		},
+		DedupeWindow: 24 * time.Hour,
+	}
+}
+
+// CalculateConfidenceScore combines every sighting's source reputation, tag
+// reliability and recency into an overall confidence score in [0,100], via
+// a noisy-OR style recurrence: each sighting independently "votes" for the
+// IOC being real, and the combined confidence is 1 minus the probability
+// that every vote was wrong -
 //
-// Current implementation: Returns 90 if multiple sources confirm the threat, 80 for single source.
-// Future: Weight by source reputation, recency, tag presence.
-func CalculateConfidenceScore(iocs []IOC) int32 {
+//	score = round(100 * (1 - prod_i(1 - w_i * r_i * d_i)))
+//
+// where for sighting i: w_i is the source's reputation weight, r_i is a
+// reliability multiplier bumped by tags like "verified", and d_i is an
+// exponential decay factor based on the sighting's age. It also returns a
+// Rationale slice of human-readable strings explaining which sightings (or
+// lack thereof) drove the score, for surfacing to callers via IOCDetails.
+func CalculateConfidenceScore(iocs []IOC, config ScoringConfig) (int32, []string) {
+	if config.HalfLifeDays <= 0 {
+		config.HalfLifeDays = DefaultScoringConfig().HalfLifeDays
+	}
+	if config.DefaultSourceWeight <= 0 {
+		config.DefaultSourceWeight = DefaultScoringConfig().DefaultSourceWeight
+	}
+
 	if len(iocs) == 0 {
-		return 0
+		return 0, []string{"no sightings found"}
 	}
 
-	// Multiple sources increase confidence
-	if len(iocs) >= 3 {
-		return 90
-	} else if len(iocs) >= 2 {
-		return 85
+	sightings := dedupeSightings(iocs, config.DedupeWindow)
+
+	probAllWrong := 1.0
+	rationale := make([]string, 0, len(sightings)+1)
+	for _, ioc := range sightings {
+		weight := sourceWeight(config, ioc.Source)
+		reliability := reliabilityMultiplier(config, ioc.Tags)
+		decay := recencyDecay(config, ioc.ThreatType, ioc.DateIngested)
+
+		term := weight * reliability * decay
+		if term > 1.0 {
+			term = 1.0
+		} else if term < 0 {
+			term = 0
+		}
+		probAllWrong *= 1 - term
+
+		rationale = append(rationale, fmt.Sprintf(
+			"%s: weight=%.2f reliability=%.2f decay=%.2f (age=%s)",
+			ioc.Source, weight, reliability, decay, time.Since(ioc.DateIngested).Round(time.Hour),
+		))
 	}
 
-	// Single source
-	return 80
+	score := int32(math.Round(100 * (1 - probAllWrong)))
+	rationale = append(rationale, fmt.Sprintf("combined from %d deduped sighting(s)", len(sightings)))
+
+	return score, rationale
+}
+
+// dedupeSightings collapses sightings from the same source that fall within
+// window of each other down to the most recent one, so repeated scraper runs
+// of the same feed don't double-count toward the score. A zero window
+// disables deduping.
+func dedupeSightings(iocs []IOC, window time.Duration) []IOC {
+	if window <= 0 {
+		return iocs
+	}
+
+	bySource := make(map[string][]IOC, len(iocs))
+	for _, ioc := range iocs {
+		bySource[ioc.Source] = append(bySource[ioc.Source], ioc)
+	}
+
+	var deduped []IOC
+	for _, group := range bySource {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].DateIngested.After(group[j].DateIngested)
+		})
+
+		var kept []IOC
+		for _, ioc := range group {
+			collapsed := false
+			for _, k := range kept {
+				if k.DateIngested.Sub(ioc.DateIngested) <= window {
+					collapsed = true
+					break
+				}
+			}
+			if !collapsed {
+				kept = append(kept, ioc)
+			}
+		}
+		deduped = append(deduped, kept...)
+	}
+
+	return deduped
+}
+
+// sourceWeight resolves w_i for a source: an exact SourceWeights match wins,
+// then the longest matching SourcePrefixWeights prefix, then DefaultSourceWeight.
+func sourceWeight(config ScoringConfig, source string) float64 {
+	if w, ok := config.SourceWeights[source]; ok {
+		return w
+	}
+
+	bestPrefix := ""
+	bestWeight := config.DefaultSourceWeight
+	for prefix, w := range config.SourcePrefixWeights {
+		if strings.HasPrefix(source, prefix) && len(prefix) > len(bestPrefix) {
+			bestPrefix = prefix
+			bestWeight = w
+		}
+	}
+	return bestWeight
+}
+
+// reliabilityMultiplier computes r_i starting at 1.0 and summing every
+// matching ReliabilityBumps entry, where a "prefix*" key matches any tag
+// sharing that prefix (e.g. "malware-family:*" matches "malware-family:emotet").
+func reliabilityMultiplier(config ScoringConfig, tags []string) float64 {
+	r := 1.0
+	for _, tag := range tags {
+		for key, bump := range config.ReliabilityBumps {
+			if strings.HasSuffix(key, "*") {
+				if strings.HasPrefix(tag, strings.TrimSuffix(key, "*")) {
+					r += bump
+				}
+			} else if tag == key {
+				r += bump
+			}
+		}
+	}
+	return r
+}
+
+// recencyDecay computes d_i = exp(-ln(2) * age_days / half_life_days), so a
+// sighting's contribution halves every half_life_days days since ingestion.
+// The half-life used is threatType's entry in HalfLifeDaysByThreatType, or
+// config.HalfLifeDays when threatType isn't listed there.
+func recencyDecay(config ScoringConfig, threatType string, dateIngested time.Time) float64 {
+	if dateIngested.IsZero() {
+		return 1.0
+	}
+	ageDays := time.Since(dateIngested).Hours() / 24
+	if ageDays < 0 {
+		ageDays = 0
+	}
+	return math.Exp(-math.Ln2 * ageDays / HalfLifeForThreatType(config, threatType))
+}
+
+// HalfLifeForThreatType resolves the decay half-life CalculateConfidenceScore
+// uses for a given threat type, falling back to config.HalfLifeDays when
+// threatType isn't listed in HalfLifeDaysByThreatType. Exported so callers
+// that need to reason about decay outside of a direct CalculateConfidenceScore
+// call (e.g. deriving a STIX indicator's valid_until) use the same half-life
+// the score itself was computed from.
+func HalfLifeForThreatType(config ScoringConfig, threatType string) float64 {
+	if days, ok := config.HalfLifeDaysByThreatType[threatType]; ok && days > 0 {
+		return days
+	}
+	if config.HalfLifeDays > 0 {
+		return config.HalfLifeDays
+	}
+	return DefaultScoringConfig().HalfLifeDays
 }
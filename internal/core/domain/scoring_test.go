@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupeSightings(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		iocs   []IOC
+		window time.Duration
+		want   int
+	}{
+		{
+			name: "same source within window collapses to one",
+			iocs: []IOC{
+				{Source: "abusech-urlhaus", DateIngested: now},
+				{Source: "abusech-urlhaus", DateIngested: now.Add(-1 * time.Hour)},
+			},
+			window: 24 * time.Hour,
+			want:   1,
+		},
+		{
+			name: "same source outside window stays separate",
+			iocs: []IOC{
+				{Source: "abusech-urlhaus", DateIngested: now},
+				{Source: "abusech-urlhaus", DateIngested: now.Add(-48 * time.Hour)},
+			},
+			window: 24 * time.Hour,
+			want:   2,
+		},
+		{
+			name: "different sources never collapse",
+			iocs: []IOC{
+				{Source: "abusech-urlhaus", DateIngested: now},
+				{Source: "alienvault-otx", DateIngested: now},
+			},
+			window: 24 * time.Hour,
+			want:   2,
+		},
+		{
+			name: "zero window disables deduping",
+			iocs: []IOC{
+				{Source: "abusech-urlhaus", DateIngested: now},
+				{Source: "abusech-urlhaus", DateIngested: now},
+			},
+			window: 0,
+			want:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupeSightings(tt.iocs, tt.window)
+			if len(got) != tt.want {
+				t.Errorf("dedupeSightings() returned %d sighting(s), want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceWeight(t *testing.T) {
+	config := ScoringConfig{
+		SourceWeights: map[string]float64{
+			"abusech-urlhaus": 0.9,
+		},
+		SourcePrefixWeights: map[string]float64{
+			"google-osv-":    0.95,
+			"google-osv-go-": 0.99,
+		},
+		DefaultSourceWeight: 0.5,
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		want   float64
+	}{
+		{
+			name:   "exact SourceWeights match wins",
+			source: "abusech-urlhaus",
+			want:   0.9,
+		},
+		{
+			name:   "longest matching prefix wins over a shorter one",
+			source: "google-osv-go-123",
+			want:   0.99,
+		},
+		{
+			name:   "shorter prefix matches when the longer one doesn't",
+			source: "google-osv-npm-123",
+			want:   0.95,
+		},
+		{
+			name:   "unmatched source falls back to DefaultSourceWeight",
+			source: "some-unknown-feed",
+			want:   0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sourceWeight(config, tt.source); got != tt.want {
+				t.Errorf("sourceWeight(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHalfLifeForThreatType(t *testing.T) {
+	config := ScoringConfig{
+		HalfLifeDays: 30,
+		HalfLifeDaysByThreatType: map[string]float64{
+			"c2_server": 7,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		threatType string
+		want       float64
+	}{
+		{
+			name:       "listed threat type uses its own half-life",
+			threatType: "c2_server",
+			want:       7,
+		},
+		{
+			name:       "unlisted threat type falls back to config.HalfLifeDays",
+			threatType: "generic_malware",
+			want:       30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HalfLifeForThreatType(config, tt.threatType); got != tt.want {
+				t.Errorf("HalfLifeForThreatType(%q) = %v, want %v", tt.threatType, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("zero config.HalfLifeDays falls back to DefaultScoringConfig", func(t *testing.T) {
+		zero := ScoringConfig{}
+		want := DefaultScoringConfig().HalfLifeDays
+		if got := HalfLifeForThreatType(zero, "generic_malware"); got != want {
+			t.Errorf("HalfLifeForThreatType() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRecencyDecay(t *testing.T) {
+	config := ScoringConfig{
+		HalfLifeDays: 30,
+		HalfLifeDaysByThreatType: map[string]float64{
+			"c2_server": 7,
+		},
+	}
+
+	t.Run("a sighting ingested just now has no decay", func(t *testing.T) {
+		decay := recencyDecay(config, "generic_malware", time.Now())
+		if decay < 0.999 || decay > 1.0 {
+			t.Errorf("expected decay ~1.0 for a fresh sighting, got %v", decay)
+		}
+	})
+
+	t.Run("a sighting one half-life old has decayed by half", func(t *testing.T) {
+		dateIngested := time.Now().Add(-7 * 24 * time.Hour)
+		decay := recencyDecay(config, "c2_server", dateIngested)
+		if decay < 0.45 || decay > 0.55 {
+			t.Errorf("expected decay ~0.5 at one half-life, got %v", decay)
+		}
+	})
+
+	t.Run("a sighting far in the past has decayed close to zero", func(t *testing.T) {
+		dateIngested := time.Now().Add(-365 * 24 * time.Hour)
+		decay := recencyDecay(config, "c2_server", dateIngested)
+		if decay > 0.01 {
+			t.Errorf("expected decay near 0 far past the half-life, got %v", decay)
+		}
+	})
+
+	t.Run("zero DateIngested means no decay is applied", func(t *testing.T) {
+		decay := recencyDecay(config, "c2_server", time.Time{})
+		if decay != 1.0 {
+			t.Errorf("expected decay 1.0 for a zero DateIngested, got %v", decay)
+		}
+	})
+}
+
+func TestCalculateConfidenceScore(t *testing.T) {
+	config := DefaultScoringConfig()
+
+	t.Run("no sightings yields zero confidence", func(t *testing.T) {
+		score, rationale := CalculateConfidenceScore(nil, config)
+		if score != 0 {
+			t.Errorf("expected score 0, got %d", score)
+		}
+		if len(rationale) != 1 {
+			t.Errorf("expected a single no-sightings rationale entry, got %v", rationale)
+		}
+	})
+
+	t.Run("more corroborating sightings raise the score", func(t *testing.T) {
+		one := []IOC{
+			{Source: "abusech-urlhaus", DateIngested: time.Now()},
+		}
+		many := []IOC{
+			{Source: "abusech-urlhaus", DateIngested: time.Now()},
+			{Source: "alienvault-otx", DateIngested: time.Now()},
+			{Source: "google-osv-go", DateIngested: time.Now()},
+		}
+
+		oneScore, _ := CalculateConfidenceScore(one, config)
+		manyScore, _ := CalculateConfidenceScore(many, config)
+		if manyScore <= oneScore {
+			t.Errorf("expected additional corroborating sightings to raise the score, got one=%d many=%d", oneScore, manyScore)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AgentRole scopes what an enrolled caller is allowed to do, so humans,
+// webhook senders, and bouncers don't all share one undifferentiated token.
+type AgentRole string
+
+const (
+	AgentRoleWebhook AgentRole = "webhook"
+	AgentRoleBouncer AgentRole = "bouncer"
+	AgentRoleAdmin   AgentRole = "admin"
+)
+
+// Agent is an enrolled caller of the Watchtower API, authenticated either by
+// a hashed API key or by an mTLS client certificate fingerprint.
+type Agent struct {
+	AgentID            string
+	Name               string
+	Role               AgentRole
+	HashedAPIKey       string // SHA-256 hex digest; never store the raw key
+	TLSCertFingerprint string // SHA-256 hex digest of the DER-encoded cert
+	EnrolledAt         time.Time
+	RevokedAt          *time.Time
+}
+
+// Revoked reports whether the agent's credentials have been revoked.
+func (a Agent) Revoked() bool {
+	return a.RevokedAt != nil
+}
+
+// AgentRepository persists enrolled agents and resolves credentials back to
+// an identity for authN/authZ in the REST middleware.
+type AgentRepository interface {
+	Create(ctx context.Context, agent Agent) error
+	FindByAPIKeyHash(ctx context.Context, hashedAPIKey string) (*Agent, error)
+	FindByCertFingerprint(ctx context.Context, fingerprint string) (*Agent, error)
+	List(ctx context.Context) ([]Agent, error)
+	Revoke(ctx context.Context, agentID string) error
+}
@@ -0,0 +1,57 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// APIKeyScope gates which public REST endpoints a key may call. Unlike
+// AgentRole (which distinguishes categories of internal caller - webhook,
+// bouncer, admin), scopes describe individual capabilities so a single
+// integration can be issued exactly the access it needs (e.g. a SIEM pull
+// job gets read:feed but not write:webhook).
+type APIKeyScope string
+
+const (
+	ScopeReadIOC      APIKeyScope = "read:ioc"
+	ScopeReadFeed     APIKeyScope = "read:feed"
+	ScopeWriteWebhook APIKeyScope = "write:webhook"
+)
+
+// APIKey is a credential issued to an external integration calling the
+// public REST API (CheckIOC, SearchIOC, GetIOCFeed, SentinelOneWebhook).
+// Only HashedKey is persisted; the raw key is returned once at creation time
+// and never stored or logged, mirroring Agent's HashedAPIKey convention.
+type APIKey struct {
+	KeyID     string
+	Name      string
+	HashedKey string
+	Scopes    []APIKeyScope
+	RateQPS   float64
+	RateBurst int
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// Revoked reports whether the key has been revoked.
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope reports whether the key was issued the given scope.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyRepository persists API keys issued for the public REST API.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key APIKey) error
+	FindByHash(ctx context.Context, hashedKey string) (*APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, keyID string) error
+}
@@ -12,11 +12,75 @@ type ThreatProvider interface {
 	Name() string
 }
 
+// IncrementalThreatProvider is an optional extension of ThreatProvider for
+// feeds that expose a per-entry modification timestamp. Implementing it lets
+// the ingester skip entries unchanged since the last sync instead of
+// reprocessing the whole feed on every run. The ingester type-asserts for
+// this interface and falls back to FetchIOCS when a provider doesn't support it.
+type IncrementalThreatProvider interface {
+	ThreatProvider
+
+	// FetchIOCSSince returns only the IOCs whose upstream entry was modified
+	// after the given watermark, plus the newest modification timestamp seen
+	// (to be persisted as the next watermark). When since is the zero value,
+	// it behaves like a full sync.
+	FetchIOCSSince(ctx context.Context, since time.Time) (iocs []domain.IOC, latestModified time.Time, err error)
+}
+
+// StreamingThreatProvider is an optional extension of ThreatProvider for feeds
+// large enough that materializing every IOC into a single slice before
+// FetchIOCS returns would blow up peak memory. The ingester type-asserts for
+// this interface so it can persist IOCs as they're parsed instead of waiting
+// for the whole feed.
+type StreamingThreatProvider interface {
+	ThreatProvider
+
+	// FetchIOCStream parses the feed incrementally, sending each IOC on the
+	// returned channel as it's ready. The error channel carries at most one
+	// terminal error (nil on clean completion) and is closed, alongside the
+	// IOC channel, once the feed has been fully read or ctx is done.
+	FetchIOCStream(ctx context.Context) (iocs <-chan domain.IOC, errs <-chan error)
+}
+
+// WatermarkRepository persists the last-synced timestamp per provider so
+// IncrementalThreatProvider feeds can resume from where they left off.
+type WatermarkRepository interface {
+	GetWatermark(ctx context.Context, provider string) (time.Time, error)
+	SetWatermark(ctx context.Context, provider string, at time.Time) error
+}
+
 type IOCRepository interface {
 	SaveBatch(ctx context.Context, iocs []domain.IOC) error
 	FindByValue(ctx context.Context, value string) (*domain.IOC, error)
 	FindAllByValue(ctx context.Context, value string) ([]domain.IOC, error)
+
+	// FindByValuesIn looks up many values in a single query, for callers that
+	// would otherwise issue one FindByValue per item (e.g. a bulk manifest
+	// scan). Values with no match are simply absent from the result, not
+	// represented as zero-value entries, so callers must key results by
+	// ioc.Value rather than relying on result order or length.
+	FindByValuesIn(ctx context.Context, values []string) ([]domain.IOC, error)
 	FindByValueAndVersion(ctx context.Context, value, version string) ([]domain.IOC, error)
 	FindContaining(ctx context.Context, value string) ([]domain.IOC, error)
 	FindSince(ctx context.Context, since time.Time, limit int) ([]domain.IOC, error)
+
+	// StreamSince walks IOCs ingested since the given watermark in fixed-size
+	// batches, invoking fn for each one, instead of materializing the full
+	// result set like FindSince does. limit caps the total number of IOCs
+	// delivered to fn, or 0 for no cap. It powers export paths (CEF, STIX)
+	// that need to serve feeds larger than comfortably fits in memory. If fn
+	// returns an error, streaming stops and that error is returned.
+	StreamSince(ctx context.Context, since time.Time, limit int, fn func(domain.IOC) error) error
+
+	// FindChangesSince returns the IOCs added and removed/expired since the given
+	// watermark, scoped to the given IOC types (empty scopes means all types).
+	// It powers the decisions-stream API so bouncers can sync state incrementally
+	// instead of re-downloading the whole feed on every poll.
+	FindChangesSince(ctx context.Context, since time.Time, scopes []domain.IOCType) (added []domain.IOC, removed []domain.IOC, err error)
+
+	// LatestIngested returns the most recent date_ingested across all IOCs,
+	// or the zero time if the table is empty. It backs the feed export's
+	// Last-Modified/ETag headers so pollers can do If-Modified-Since instead
+	// of re-downloading a feed that hasn't changed.
+	LatestIngested(ctx context.Context) (time.Time, error)
 }
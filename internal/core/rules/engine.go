@@ -0,0 +1,202 @@
+package rules
+
+import (
+	"container/list"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// defaultCacheSize is the Engine result cache size used when NewEngine is
+// given cacheSize <= 0.
+const defaultCacheSize = 10000
+
+// Decision is the merged effect of every Rule that matched during one or
+// more Engine.EvaluateIOC calls.
+type Decision struct {
+	Severity           string   // last ActionSetSeverity to match wins
+	ForceFalsePositive bool     // true if any ActionForceFalsePositive matched
+	SkipLLM            bool     // true if any ActionSkipLLM matched
+	Tags               []string // accumulated ActionTag values, in match order
+	Queue              string   // last ActionRouteToQueue to match wins
+	MatchedRules       []string // names of every rule that matched, in evaluation order
+}
+
+// Merge folds other into d: booleans OR together, Tags/MatchedRules
+// concatenate, and Severity/Queue keep d's own value unless other set one
+// and d didn't - so folding several IOCs' decisions for one alert doesn't
+// let a later, weaker IOC silently overwrite an earlier strong match.
+func (d *Decision) Merge(other Decision) {
+	if d.Severity == "" {
+		d.Severity = other.Severity
+	}
+	if d.Queue == "" {
+		d.Queue = other.Queue
+	}
+	d.ForceFalsePositive = d.ForceFalsePositive || other.ForceFalsePositive
+	d.SkipLLM = d.SkipLLM || other.SkipLLM
+	d.Tags = append(d.Tags, other.Tags...)
+	d.MatchedRules = append(d.MatchedRules, other.MatchedRules...)
+}
+
+func (d *Decision) apply(actions []Action) {
+	for _, a := range actions {
+		switch a.Type {
+		case ActionSetSeverity:
+			d.Severity = a.Value
+		case ActionForceFalsePositive:
+			d.ForceFalsePositive = true
+		case ActionSkipLLM:
+			d.SkipLLM = true
+		case ActionTag:
+			d.Tags = append(d.Tags, a.Value)
+		case ActionRouteToQueue:
+			d.Queue = a.Value
+		}
+		RecordRuleAction(a.Type)
+	}
+}
+
+type compiledRule struct {
+	Rule
+	program *vm.Program
+	hash    string
+}
+
+type cacheEntry struct {
+	key     string
+	matched bool
+}
+
+// Engine evaluates a compiled RuleSet against caller-supplied env maps,
+// caching each (IOC value, rule) result in a bounded LRU so repeated alerts
+// for the same indicator within a burst don't re-run every expression.
+type Engine struct {
+	rules []compiledRule
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	maxSize int
+}
+
+// NewEngine compiles every rule in set.Rules and returns an Engine backed
+// by an LRU cache of at most cacheSize results (defaulting to
+// defaultCacheSize when cacheSize <= 0).
+func NewEngine(set *RuleSet, cacheSize int) (*Engine, error) {
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+
+	compiled := make([]compiledRule, 0, len(set.Rules))
+	for _, rule := range set.Rules {
+		program, err := expr.Compile(rule.When, expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: failed to compile when: %w", rule.Name, err)
+		}
+		compiled = append(compiled, compiledRule{
+			Rule:    rule,
+			program: program,
+			hash:    ruleHash(rule),
+		})
+	}
+
+	return &Engine{
+		rules:   compiled,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		maxSize: cacheSize,
+	}, nil
+}
+
+// EvaluateIOC runs every compiled rule's When expression against env
+// (typically {"ioc": ..., "threat": ..., "count": ...}), keying the result
+// cache on iocValue plus each rule's hash so a repeat alert for the same
+// indicator skips re-evaluation entirely. It returns the merged Decision of
+// every rule that matched.
+func (e *Engine) EvaluateIOC(iocValue string, env map[string]interface{}) Decision {
+	var decision Decision
+
+	for _, rule := range e.rules {
+		cacheKey := iocValue + "|" + rule.hash
+
+		if matched, ok := e.getCached(cacheKey); ok {
+			RecordRuleEvaluation(rule.Name, "cache_hit")
+			if matched {
+				decision.apply(rule.Actions)
+				decision.MatchedRules = append(decision.MatchedRules, rule.Name)
+			}
+			continue
+		}
+
+		out, err := expr.Run(rule.program, env)
+		if err != nil {
+			RecordRuleEvaluation(rule.Name, "error")
+			continue
+		}
+
+		matched, _ := out.(bool)
+		e.putCached(cacheKey, matched)
+
+		if matched {
+			RecordRuleEvaluation(rule.Name, "hit")
+			decision.apply(rule.Actions)
+			decision.MatchedRules = append(decision.MatchedRules, rule.Name)
+		} else {
+			RecordRuleEvaluation(rule.Name, "miss")
+		}
+	}
+
+	return decision
+}
+
+func (e *Engine) getCached(key string) (bool, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	elem, ok := e.entries[key]
+	if !ok {
+		return false, false
+	}
+	e.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).matched, true
+}
+
+func (e *Engine) putCached(key string, matched bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if elem, ok := e.entries[key]; ok {
+		elem.Value.(*cacheEntry).matched = matched
+		e.order.MoveToFront(elem)
+		return
+	}
+
+	elem := e.order.PushFront(&cacheEntry{key: key, matched: matched})
+	e.entries[key] = elem
+
+	for e.order.Len() > e.maxSize {
+		oldest := e.order.Back()
+		if oldest == nil {
+			break
+		}
+		e.order.Remove(oldest)
+		delete(e.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// ruleHash derives a stable identifier for a rule from its name and When
+// expression, so editing a rule's condition invalidates its own cached
+// results without requiring a cache-wide flush.
+func ruleHash(rule Rule) string {
+	h := fnv.New64a()
+	io.WriteString(h, rule.Name)
+	io.WriteString(h, "\x00")
+	io.WriteString(h, rule.When)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
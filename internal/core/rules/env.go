@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// EngineFromEnv builds an Engine from RULES_FILE (a single rules YAML file)
+// or RULES_DIR (a directory of *.yaml/*.yml files, merged together), with
+// RULES_CACHE_SIZE controlling the result cache size. Returns (nil, nil)
+// when neither is set, so callers can treat a nil Engine as "rules engine
+// disabled".
+func EngineFromEnv() (*Engine, error) {
+	set, err := loadRuleSetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return nil, nil
+	}
+
+	cacheSize := 0
+	if raw := os.Getenv("RULES_CACHE_SIZE"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			cacheSize = n
+		}
+	}
+
+	return NewEngine(set, cacheSize)
+}
+
+func loadRuleSetFromEnv() (*RuleSet, error) {
+	if path := os.Getenv("RULES_FILE"); path != "" {
+		return loadRuleSetFromFile(path)
+	}
+
+	dir := os.Getenv("RULES_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RULES_DIR: %w", err)
+	}
+
+	merged := &RuleSet{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		set, err := loadRuleSetFromFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		merged.Rules = append(merged.Rules, set.Rules...)
+	}
+	if len(merged.Rules) == 0 {
+		return nil, nil
+	}
+	return merged, nil
+}
+
+func loadRuleSetFromFile(path string) (*RuleSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	set, err := LoadRuleSet(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
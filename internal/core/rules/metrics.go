@@ -0,0 +1,52 @@
+package rules
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricsOnce sync.Once
+
+	rulesEvaluationsTotal *prometheus.CounterVec
+	rulesActionsTotal     *prometheus.CounterVec
+)
+
+// InitMetrics registers the rules engine's Prometheus metrics. Safe to call
+// more than once; only the first call takes effect.
+func InitMetrics() {
+	metricsOnce.Do(func() {
+		rulesEvaluationsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rules_evaluations_total",
+				Help: "Total number of rule evaluations by rule name and outcome (hit, miss, cache_hit, error)",
+			},
+			[]string{"rule", "outcome"},
+		)
+
+		rulesActionsTotal = promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rules_actions_total",
+				Help: "Total number of rule actions applied, by action type",
+			},
+			[]string{"action"},
+		)
+	})
+}
+
+// RecordRuleEvaluation records one rule evaluation outcome: "hit", "miss",
+// "cache_hit", or "error".
+func RecordRuleEvaluation(rule, outcome string) {
+	if rulesEvaluationsTotal != nil {
+		rulesEvaluationsTotal.WithLabelValues(rule, outcome).Inc()
+	}
+}
+
+// RecordRuleAction records one action application, by its ActionType.
+func RecordRuleAction(action ActionType) {
+	if rulesActionsTotal != nil {
+		rulesActionsTotal.WithLabelValues(string(action)).Inc()
+	}
+}
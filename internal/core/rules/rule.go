@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ActionType names one of the effects a matched Rule can have on a
+// Decision. New action types should be added here and handled in
+// Decision.apply.
+type ActionType string
+
+const (
+	// ActionSetSeverity overrides the triage severity with Value.
+	ActionSetSeverity ActionType = "set_severity"
+	// ActionForceFalsePositive marks the alert a false positive outright.
+	ActionForceFalsePositive ActionType = "force_false_positive"
+	// ActionSkipLLM short-circuits the LLM call entirely, the same as a
+	// pre-LLM guardrail hit.
+	ActionSkipLLM ActionType = "skip_llm"
+	// ActionTag appends Value to the result's accumulated tags.
+	ActionTag ActionType = "tag"
+	// ActionRouteToQueue overrides which downstream queue the alert is
+	// routed to with Value.
+	ActionRouteToQueue ActionType = "route_to_queue"
+)
+
+// Action is one effect a Rule applies when its When expression matches.
+// Value is interpreted per Type: the severity string for ActionSetSeverity,
+// the tag for ActionTag, the queue name for ActionRouteToQueue, and ignored
+// for ActionForceFalsePositive/ActionSkipLLM.
+type Action struct {
+	Type  ActionType `yaml:"type"`
+	Value string     `yaml:"value,omitempty"`
+}
+
+// Rule is one scriptable enrichment/triage rule. When is an expr-lang
+// boolean expression evaluated against a caller-supplied env (typically
+// {"ioc": ..., "threat": ...}); Actions lists what happens when it matches.
+type Rule struct {
+	Name    string   `yaml:"name"`
+	When    string   `yaml:"when"`
+	Actions []Action `yaml:"actions"`
+}
+
+// RuleSet is the top-level shape of a rules YAML file:
+//
+//	rules:
+//	  - name: confirmed-c2
+//	    when: ioc.Type == "ip" && "c2_server" in ioc.ThreatTypes && count(ioc.Sources) >= 2
+//	    actions:
+//	      - type: set_severity
+//	        value: critical
+//	      - type: tag
+//	        value: confirmed-c2
+type RuleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadRuleSet parses a rules YAML document from r, rejecting any rule
+// missing a name or a when expression.
+func LoadRuleSet(r io.Reader) (*RuleSet, error) {
+	var set RuleSet
+	if err := yaml.NewDecoder(r).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to parse rule set: %w", err)
+	}
+
+	for i, rule := range set.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i)
+		}
+		if rule.When == "" {
+			return nil, fmt.Errorf("rule %q: when is required", rule.Name)
+		}
+	}
+
+	return &set, nil
+}
@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hive-corporation/watchtower/internal/adapter/handler"
+	"github.com/hive-corporation/watchtower/internal/core/domain"
+)
+
+func TestDecisionsStream_StartupReturnsFullSet(t *testing.T) {
+	repo := newMockRepository()
+	repo.iocs["evil.com"] = &domain.IOC{
+		Value:        "evil.com",
+		Type:         domain.Domain,
+		Source:       "test",
+		DateIngested: time.Now(),
+	}
+
+	h := handler.NewRestHandler(repo, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/decisions/stream?startup=true", nil)
+	rec := httptest.NewRecorder()
+
+	h.DecisionsStream(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	if rec.Header().Get(handler.CursorHeader) == "" {
+		t.Error("expected a cursor header on the response")
+	}
+
+	var body struct {
+		New     []map[string]interface{} `json:"new"`
+		Deleted []map[string]interface{} `json:"deleted"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(body.New) != 1 {
+		t.Errorf("expected 1 new entry, got %d", len(body.New))
+	}
+}
+
+func TestDecisionsStream_RejectsInvalidCursor(t *testing.T) {
+	repo := newMockRepository()
+	h := handler.NewRestHandler(repo, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/decisions/stream?cursor=not-valid-base64!!", nil)
+	rec := httptest.NewRecorder()
+
+	h.DecisionsStream(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed cursor, got %d", rec.Code)
+	}
+}
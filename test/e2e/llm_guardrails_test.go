@@ -41,6 +41,16 @@ func (m *mockRepository) FindAllByValue(ctx context.Context, value string) ([]do
 	return results, nil
 }
 
+func (m *mockRepository) FindByValuesIn(ctx context.Context, values []string) ([]domain.IOC, error) {
+	var results []domain.IOC
+	for _, value := range values {
+		if ioc, exists := m.iocs[value]; exists {
+			results = append(results, *ioc)
+		}
+	}
+	return results, nil
+}
+
 func (m *mockRepository) FindByValueAndVersion(ctx context.Context, value, version string) ([]domain.IOC, error) {
 	var results []domain.IOC
 	if ioc, exists := m.iocs[value]; exists {
@@ -83,6 +93,39 @@ func (m *mockRepository) FindSince(ctx context.Context, since time.Time, limit i
 	return results, nil
 }
 
+func (m *mockRepository) StreamSince(ctx context.Context, since time.Time, limit int, fn func(domain.IOC) error) error {
+	iocs, err := m.FindSince(ctx, since, limit)
+	if err != nil {
+		return err
+	}
+	for _, ioc := range iocs {
+		if err := fn(ioc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) FindChangesSince(ctx context.Context, since time.Time, scopes []domain.IOCType) ([]domain.IOC, []domain.IOC, error) {
+	var added []domain.IOC
+	for _, ioc := range m.iocs {
+		if ioc.DateIngested.After(since) {
+			added = append(added, *ioc)
+		}
+	}
+	return added, nil, nil
+}
+
+func (m *mockRepository) LatestIngested(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, ioc := range m.iocs {
+		if ioc.DateIngested.After(latest) {
+			latest = ioc.DateIngested
+		}
+	}
+	return latest, nil
+}
+
 // Mock LLM server
 func createMockLLMServer(t *testing.T, responseFunc func(*http.Request) map[string]interface{}) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {